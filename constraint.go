@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// constraintGroup is a oneof or anyof group of optional fields in the order they are declared
+type constraintGroup struct {
+	kind   string
+	name   string
+	fields []string
+}
+
+// hasConstraints returns true when any field passed in is constrained with oneof, anyof or requires
+func hasConstraints(fields []genField) bool {
+	for _, p := range ownParams(fields) {
+		if p.field.oneOf != "" || p.field.anyOf != "" || len(p.field.requires) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// constraintGroups returns the oneof and anyof groups in the order they are first declared
+func constraintGroups(fields []genField) []*constraintGroup {
+	var groups []*constraintGroup
+	byKey := make(map[string]*constraintGroup)
+	add := func(kind, name, field string) {
+		key := kind + "=" + name
+		g, ok := byKey[key]
+		if !ok {
+			g = &constraintGroup{kind: kind, name: name}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.fields = append(g.fields, field)
+	}
+
+	for _, p := range ownParams(fields) {
+		if p.field.oneOf != "" {
+			add(tagOneOf, p.field.oneOf, p.name)
+		}
+		if p.field.anyOf != "" {
+			add(tagAnyOf, p.field.anyOf, p.name)
+		}
+	}
+	return groups
+}
+
+// joinNames returns the names as a list for an error message, e.g. A, B or C
+func joinNames(names []string) string {
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// buildConstraints returns the statements returning an error when the optional fields passed in break a oneof, anyof
+// or requires constraint
+func buildConstraints(funcName, name string, fields []genField) string {
+	params := make(map[string]genParam)
+	for _, p := range ownParams(fields) {
+		params[p.field.name] = p
+		if (p.field.oneOf != "" || p.field.anyOf != "" || len(p.field.requires) > 0) && !p.field.optional {
+			log.Panicf("constrained field [%s] in struct [%s] must be optional", p.name, name)
+		}
+	}
+
+	var sb strings.Builder
+	for _, g := range constraintGroups(fields) {
+		if len(g.fields) < 2 {
+			log.Panicf("%s group [%s] in struct [%s] requires at least two fields", g.kind, g.name, name)
+		}
+
+		switch g.kind {
+		case tagOneOf:
+			sb.WriteString("{\nn := 0\n")
+			for _, f := range g.fields {
+				sb.WriteString(fmt.Sprintf("if %s != nil {\nn++\n}\n", f))
+			}
+			sb.WriteString(fmt.Sprintf("if n != 1 {\nreturn nil, fmt.Errorf(\"%s: exactly one of %s must be set\")\n}\n}\n", funcName, joinNames(g.fields)))
+		case tagAnyOf:
+			var unset []string
+			for _, f := range g.fields {
+				unset = append(unset, f+" == nil")
+			}
+			sb.WriteString(fmt.Sprintf("if %s {\nreturn nil, fmt.Errorf(\"%s: at least one of %s must be set\")\n}\n", strings.Join(unset, " && "), funcName, joinNames(g.fields)))
+		}
+	}
+
+	for _, p := range ownParams(fields) {
+		for _, required := range p.field.requires {
+			target, ok := params[required]
+			if !ok {
+				log.Panicf("field [%s] in struct [%s] requires unknown field [%s]", p.name, name, required)
+			}
+			if !target.field.optional {
+				log.Panicf("field [%s] in struct [%s] requires [%s], which must be optional", p.name, name, required)
+			}
+			sb.WriteString(fmt.Sprintf("if %s != nil && %s == nil {\nreturn nil, fmt.Errorf(\"%s: %s must be set when %s is set\")\n}\n", p.name, target.name, funcName, target.name, p.name))
+		}
+	}
+	return sb.String()
+}
+
+// constrainedFields returns the optional fields which are members of a oneof or anyof group, require other fields or
+// are required by them, in the order they are declared
+func constrainedFields(fields []genField) []genField {
+	required := make(map[string]bool)
+	for _, p := range ownParams(fields) {
+		for _, r := range p.field.requires {
+			required[r] = true
+		}
+	}
+
+	var result []genField
+	for _, p := range ownParams(fields) {
+		f := p.field
+		if f.optional && (f.oneOf != "" || f.anyOf != "" || len(f.requires) > 0 || required[f.name]) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// isConstrained returns true when the field is one of the constrained fields
+func isConstrained(fields []genField, name string) bool {
+	for _, f := range constrainedFields(fields) {
+		if f.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredFields returns the fields each field requires, either directly or through the fields it requires
+func requiredFields(fields []genField) map[string][]string {
+	direct := make(map[string][]string)
+	for _, p := range ownParams(fields) {
+		direct[p.field.name] = p.field.requires
+	}
+
+	result := make(map[string][]string)
+	for name := range direct {
+		seen := map[string]bool{name: true}
+		pending := append([]string{}, direct[name]...)
+		for len(pending) > 0 {
+			r := pending[0]
+			pending = pending[1:]
+			if seen[r] {
+				continue
+			}
+			seen[r] = true
+			result[name] = append(result[name], r)
+			pending = append(pending, direct[r]...)
+		}
+	}
+	return result
+}
+
+// constraintConflict returns the reason values satisfying the constraints can't be generated, as setting a field of
+// a oneof group for another group or for a field requiring it could set more than one field of the group
+func constraintConflict(fields []genField) (string, bool) {
+	required := make(map[string]string)
+	for name, targets := range requiredFields(fields) {
+		for _, r := range targets {
+			if other, ok := required[r]; !ok || name < other {
+				required[r] = name
+			}
+		}
+	}
+
+	for _, f := range constrainedFields(fields) {
+		switch {
+		case f.oneOf != "" && f.anyOf != "":
+			return fmt.Sprintf("[%s] is in both oneof group [%s] and anyof group [%s]", f.name, f.oneOf, f.anyOf), true
+		case f.oneOf != "" && required[f.name] != "":
+			return fmt.Sprintf("[%s] in oneof group [%s] is required by [%s]", f.name, f.oneOf, required[f.name]), true
+		}
+	}
+	return "", false
+}
+
+// satisfyingFields returns whether each constrained field is set by generated tests, fuzz tests and examples, only the
+// first field of each oneof group is set while every other constrained field is set, which satisfies any requires
+// as fields of oneof groups can't be required
+func satisfyingFields(fields []genField) map[string]bool {
+	result := make(map[string]bool)
+	for _, f := range constrainedFields(fields) {
+		result[f.name] = f.oneOf == ""
+	}
+	for _, g := range constraintGroups(fields) {
+		if g.kind != tagOneOf {
+			continue
+		}
+		for _, p := range ownParams(fields) {
+			if p.name == g.fields[0] {
+				result[p.field.name] = true
+			}
+		}
+	}
+	return result
+}
+
+// formatSetName returns the local variable of a quick.Generator holding whether the constrained field is set
+func formatSetName(field string) string {
+	return "set" + field
+}
+
+// buildQuickConstraints returns the statements of a quick.Generator randomly choosing which constrained fields are
+// set, exactly one field of each oneof group and at least one field of each anyof group is set along with the fields
+// required by any field which is set
+func buildQuickConstraints(fields []genField) string {
+	constrained := constrainedFields(fields)
+	if len(constrained) == 0 {
+		return ""
+	}
+
+	byParam := make(map[string]genField)
+	for _, p := range ownParams(fields) {
+		byParam[p.name] = p.field
+	}
+
+	var sb strings.Builder
+	for _, f := range constrained {
+		if f.oneOf != "" {
+			sb.WriteString(fmt.Sprintf("%s := false\n", formatSetName(f.name)))
+		} else {
+			sb.WriteString(fmt.Sprintf("%s := r.Intn(2) == 1\n", formatSetName(f.name)))
+		}
+	}
+
+	for _, g := range constraintGroups(fields) {
+		sb.WriteString(fmt.Sprintf("switch r.Intn(%d) {\n", len(g.fields)))
+		for i, name := range g.fields {
+			sb.WriteString(fmt.Sprintf("case %d:\n%s = true\n", i, formatSetName(byParam[name].name)))
+		}
+		sb.WriteString("}\n")
+	}
+
+	required := requiredFields(fields)
+	for _, f := range constrained {
+		if len(required[f.name]) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("if %s {\n", formatSetName(f.name)))
+		for _, r := range required[f.name] {
+			sb.WriteString(fmt.Sprintf("%s = true\n", formatSetName(r)))
+		}
+		sb.WriteString("}\n")
+	}
+	return sb.String()
+}
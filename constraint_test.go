@@ -0,0 +1,123 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestJoinNames(t *testing.T) {
+	assert.Equal(t, "A", joinNames([]string{"A"}))
+	assert.Equal(t, "A or B", joinNames([]string{"A", "B"}))
+	assert.Equal(t, "A, B or C", joinNames([]string{"A", "B", "C"}))
+}
+
+func TestConstraintGroups(t *testing.T) {
+	fields := []genField{
+		{name: "Email", typ: "string", optional: true, anyOf: "contact"},
+		{name: "File", typ: "string", optional: true, oneOf: "source"},
+		{name: "Phone", typ: "string", optional: true, anyOf: "contact"},
+		{name: "URL", typ: "string", optional: true, oneOf: "source"},
+	}
+	groups := constraintGroups(fields)
+	assert.Len(t, groups, 2)
+	assert.Equal(t, constraintGroup{kind: "anyof", name: "contact", fields: []string{"Email", "Phone"}}, *groups[0])
+	assert.Equal(t, constraintGroup{kind: "oneof", name: "source", fields: []string{"File", "URL"}}, *groups[1])
+	assert.True(t, hasConstraints(fields))
+	assert.False(t, hasConstraints([]genField{{name: "Name", typ: "string"}}))
+}
+
+func TestBuildConstraints(t *testing.T) {
+	t.Run("constraints", func(t *testing.T) {
+		fields := []genField{
+			{name: "File", typ: "string", optional: true, oneOf: "source"},
+			{name: "URL", typ: "string", optional: true, oneOf: "source"},
+			{name: "Email", typ: "string", optional: true, anyOf: "contact"},
+			{name: "Phone", typ: "string", optional: true, anyOf: "contact"},
+			{name: "Username", typ: "string", optional: true, requires: []string{"Password"}},
+			{name: "Password", typ: "string", optional: true},
+		}
+		expected := "{\nn := 0\nif File != nil {\nn++\n}\nif URL != nil {\nn++\n}\n" +
+			"if n != 1 {\nreturn nil, fmt.Errorf(\"NewSample: exactly one of File or URL must be set\")\n}\n}\n" +
+			"if Email == nil && Phone == nil {\nreturn nil, fmt.Errorf(\"NewSample: at least one of Email or Phone must be set\")\n}\n" +
+			"if Username != nil && Password == nil {\nreturn nil, fmt.Errorf(\"NewSample: Password must be set when Username is set\")\n}\n"
+		assert.Equal(t, expected, buildConstraints("NewSample", "Sample", fields))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Panics(t, func() {
+			buildConstraints("NewSample", "Sample", []genField{
+				{name: "File", typ: "string", oneOf: "source"},
+				{name: "URL", typ: "string", optional: true, oneOf: "source"},
+			})
+		})
+		assert.Panics(t, func() {
+			buildConstraints("NewSample", "Sample", []genField{{name: "File", typ: "string", optional: true, oneOf: "source"}})
+		})
+		assert.Panics(t, func() {
+			buildConstraints("NewSample", "Sample", []genField{{name: "Username", typ: "string", optional: true, requires: []string{"Missing"}}})
+		})
+		assert.Panics(t, func() {
+			buildConstraints("NewSample", "Sample", []genField{
+				{name: "Username", typ: "string", optional: true, requires: []string{"Password"}},
+				{name: "Password", typ: "string"},
+			})
+		})
+	})
+}
+
+func TestRequiredFields(t *testing.T) {
+	fields := []genField{
+		{name: "Username", typ: "string", optional: true, requires: []string{"Password"}},
+		{name: "Password", typ: "string", optional: true, requires: []string{"Salt"}},
+		{name: "Salt", typ: "string", optional: true},
+	}
+	assert.Equal(t, map[string][]string{"Username": {"Password", "Salt"}, "Password": {"Salt"}}, requiredFields(fields))
+	assert.Len(t, constrainedFields(fields), 3)
+	assert.True(t, isConstrained(fields, "Salt"))
+	assert.False(t, isConstrained([]genField{{name: "Name", typ: "string", optional: true}}, "Name"))
+}
+
+func TestConstraintConflict(t *testing.T) {
+	_, ok := constraintConflict([]genField{
+		{name: "File", typ: "string", optional: true, oneOf: "source"},
+		{name: "URL", typ: "string", optional: true, oneOf: "source", requires: []string{"Token"}},
+		{name: "Token", typ: "string", optional: true},
+	})
+	assert.False(t, ok)
+
+	reason, ok := constraintConflict([]genField{
+		{name: "File", typ: "string", optional: true, oneOf: "source"},
+		{name: "URL", typ: "string", optional: true, oneOf: "source"},
+		{name: "Token", typ: "string", optional: true, requires: []string{"URL"}},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "[URL] in oneof group [source] is required by [Token]", reason)
+}
+
+func TestSatisfyingFields(t *testing.T) {
+	fields := []genField{
+		{name: "File", typ: "string", optional: true, oneOf: "source"},
+		{name: "URL", typ: "string", optional: true, oneOf: "source"},
+		{name: "Email", typ: "string", optional: true, anyOf: "contact"},
+		{name: "Phone", typ: "string", optional: true, anyOf: "contact"},
+		{name: "Username", typ: "string", optional: true, requires: []string{"Password"}},
+		{name: "Password", typ: "string", optional: true},
+		{name: "Age", typ: "int", optional: true},
+	}
+	expected := map[string]bool{"File": true, "URL": false, "Email": true, "Phone": true, "Username": true, "Password": true}
+	assert.Equal(t, expected, satisfyingFields(fields))
+}
+
+func TestBuildQuickConstraints(t *testing.T) {
+	assert.Empty(t, buildQuickConstraints([]genField{{name: "Age", typ: "int", optional: true}}))
+
+	fields := []genField{
+		{name: "Email", typ: "string", optional: true, anyOf: "contact"},
+		{name: "Phone", typ: "string", optional: true, anyOf: "contact", requires: []string{"Region"}},
+		{name: "Region", typ: "string", optional: true},
+	}
+	expected := "setEmail := r.Intn(2) == 1\nsetPhone := r.Intn(2) == 1\nsetRegion := r.Intn(2) == 1\n" +
+		"switch r.Intn(2) {\ncase 0:\nsetEmail = true\ncase 1:\nsetPhone = true\n}\n" +
+		"if setPhone {\nsetRegion = true\n}\n"
+	assert.Equal(t, expected, buildQuickConstraints(fields))
+}
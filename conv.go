@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// convertedVar returns the variable holding the converted value of a param
+func convertedVar(f genField) string {
+	return f.paramName() + "Converted"
+}
+
+// hasConversions returns true when any param of the fields passed in is converted, including flattened params
+func hasConversions(fields []genField) bool {
+	for _, p := range factoryParams(fields) {
+		if p.field.conv != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// buildConversions returns the statements converting each input param of a field tagged with fmgen:"conv=Func" to the
+// type of the field, returning an error when the conversion fails. The fields are returned assigned from the converted
+// values instead of their params
+func buildConversions(funcName, name string, fields []genField) (string, []genField) {
+	var sb strings.Builder
+	converted := make([]genField, len(fields))
+	for i, f := range fields {
+		converted[i] = f
+		if f.conv == "" || f.skip || f.gen != "" {
+			continue
+		}
+		if f.nilable() || f.iface || f.flatten != "" {
+			log.Panicf("conv tag can't be used on field [%s] in struct [%s] of type %s", f.name, name, valueType(f))
+		}
+		if f.ptr && !f.optional && len(f.normalize) > 0 {
+			log.Panicf("conv tag can't be used with normalize tags on required pointer field [%s] in struct [%s]", f.name, name)
+		}
+
+		// the converter returns a pointer for pointer fields, such as url.Parse
+		param, v := f.paramName(), convertedVar(f)
+		onError := fmt.Sprintf("if err != nil {\nreturn nil, fmt.Errorf(\"%s: invalid %s: %%w\", err)\n}\n", funcName, param)
+		switch {
+		case f.optional && f.ptr:
+			sb.WriteString(fmt.Sprintf("var %s *%s\nif %s != nil {\nv, err := %s(*%s)\n%s%s = v\n}\n", v, f.typ, param, f.conv, param, onError, v))
+		case f.optional:
+			sb.WriteString(fmt.Sprintf("var %s *%s\nif %s != nil {\nv, err := %s(*%s)\n%s%s = &v\n}\n", v, f.typ, param, f.conv, param, onError, v))
+		default:
+			sb.WriteString(fmt.Sprintf("%s, err := %s(%s)\n%s", v, f.conv, param, onError))
+			// a converted pointer is assigned as is
+			f.ptr = false
+		}
+
+		// the field is assigned from the converted value
+		f.param = v
+		f.conv = ""
+		f.paramType = ""
+		converted[i] = f
+	}
+	return sb.String(), converted
+}
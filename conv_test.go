@@ -0,0 +1,53 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHasConversions(t *testing.T) {
+	assert.False(t, hasConversions([]genField{{name: "Name", typ: "string"}}))
+	assert.True(t, hasConversions([]genField{{name: "ID", typ: "uuid.UUID", conv: "uuid.Parse", paramType: "string"}}))
+
+	nested := []genField{{name: "Wait", typ: "time.Duration", conv: "time.ParseDuration", paramType: "string"}}
+	assert.True(t, hasConversions([]genField{{name: "Inner", typ: "Inner", flatten: "flatten", nested: nested}}))
+	assert.False(t, hasConversions([]genField{{name: "ID", typ: "uuid.UUID", conv: "uuid.Parse", skip: true}}))
+}
+
+func TestBuildConversions(t *testing.T) {
+	fields := []genField{
+		{name: "Name", typ: "string", param: "name"},
+		{name: "ID", typ: "uuid.UUID", conv: "uuid.Parse", paramType: "string", param: "id"},
+		{name: "Endpoint", typ: "url.URL", ptr: true, conv: "url.Parse", paramType: "string", param: "endpoint"},
+		{name: "Created", typ: "time.Time", optional: true, conv: "parseUnix", paramType: "int64", param: "created"},
+		{name: "Home", typ: "url.URL", ptr: true, optional: true, conv: "url.Parse", paramType: "string", param: "home"},
+	}
+	expected := "idConverted, err := uuid.Parse(id)\n" +
+		"if err != nil {\nreturn nil, fmt.Errorf(\"NewUser: invalid id: %w\", err)\n}\n" +
+		"endpointConverted, err := url.Parse(endpoint)\n" +
+		"if err != nil {\nreturn nil, fmt.Errorf(\"NewUser: invalid endpoint: %w\", err)\n}\n" +
+		"var createdConverted *time.Time\nif created != nil {\nv, err := parseUnix(*created)\n" +
+		"if err != nil {\nreturn nil, fmt.Errorf(\"NewUser: invalid created: %w\", err)\n}\ncreatedConverted = &v\n}\n" +
+		"var homeConverted *url.URL\nif home != nil {\nv, err := url.Parse(*home)\n" +
+		"if err != nil {\nreturn nil, fmt.Errorf(\"NewUser: invalid home: %w\", err)\n}\nhomeConverted = v\n}\n"
+
+	body, assigned := buildConversions("NewUser", "User", fields)
+	assert.Equal(t, expected, body)
+	assert.Equal(t, fields[0], assigned[0])
+	assert.Equal(t, genField{name: "ID", typ: "uuid.UUID", param: "idConverted"}, assigned[1])
+	assert.Equal(t, genField{name: "Endpoint", typ: "url.URL", param: "endpointConverted"}, assigned[2])
+	assert.Equal(t, genField{name: "Created", typ: "time.Time", optional: true, param: "createdConverted"}, assigned[3])
+	assert.Equal(t, genField{name: "Home", typ: "url.URL", ptr: true, optional: true, param: "homeConverted"}, assigned[4])
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Panics(t, func() {
+			buildConversions("NewUser", "User", []genField{{name: "Tags", typ: "string", array: true, conv: "parseTags", paramType: "string"}})
+		})
+		assert.Panics(t, func() {
+			buildConversions("NewUser", "User", []genField{{name: "Inner", typ: "Inner", flatten: "flatten", conv: "parseInner", paramType: "string"}})
+		})
+		assert.Panics(t, func() {
+			buildConversions("NewUser", "User", []genField{{name: "Endpoint", typ: "url.URL", ptr: true, conv: "url.Parse", paramType: "string", normalize: []string{"trim"}}})
+		})
+	})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// shared returns true for slices and maps, which share their storage when assigned
+func shared(typ ast.Expr) bool {
+	switch t := typ.(type) {
+	case *ast.ArrayType:
+		return t.Len == nil
+	case *ast.MapType:
+		return true
+	}
+	return false
+}
+
+// buildCopy returns the statements assigning a copy of the source slice or map to the target, nested slices and maps
+// are copied as well. The depth keeps the variables of nested copies unique
+func buildCopy(target, source string, typ ast.Expr, depth int) string {
+	var suffix string
+	if depth > 1 {
+		suffix = strconv.Itoa(depth)
+	}
+	c, i, k, v := "c"+suffix, "i"+suffix, "k"+suffix, "v"+suffix
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("if %s != nil {\n", source))
+	sb.WriteString(fmt.Sprintf("%s := make(%s, len(%s))\n", c, types.ExprString(typ), source))
+	switch t := typ.(type) {
+	case *ast.ArrayType:
+		if shared(t.Elt) {
+			sb.WriteString(fmt.Sprintf("for %s, %s := range %s {\n%s}\n", i, v, source, buildCopy(c+"["+i+"]", v, t.Elt, depth+1)))
+		} else {
+			sb.WriteString(fmt.Sprintf("copy(%s, %s)\n", c, source))
+		}
+	case *ast.MapType:
+		sb.WriteString(fmt.Sprintf("for %s, %s := range %s {\n%s[%s] = %s\n", k, v, source, c, k, v))
+		if shared(t.Value) {
+			sb.WriteString(buildCopy(c+"["+k+"]", v, t.Value, depth+1))
+		}
+		sb.WriteString("}\n")
+	}
+	sb.WriteString(fmt.Sprintf("%s = %s\n}\n", target, c))
+	return sb.String()
+}
+
+// buildCopies returns the statements replacing each input param of a field tagged with fmgen:"copy" with a copy, so
+// the caller's arrays and maps are not shared with the result
+func buildCopies(name string, fields []genField) string {
+	var sb strings.Builder
+	for _, p := range ownParams(fields) {
+		f := p.field
+		if !f.copy {
+			continue
+		}
+		if !f.array && !f.mapped {
+			log.Panicf("copy tag requires an array or map field, [%s] in struct [%s] is %s", f.name, name, valueType(f))
+		}
+
+		typ, err := parser.ParseExpr(p.typ)
+		if err != nil {
+			log.Panicf("unable to parse type [%s] of field [%s] in struct [%s] - %v", p.typ, f.name, name, err)
+		}
+		sb.WriteString(buildCopy(p.name, p.name, typ, 1))
+	}
+	return sb.String()
+}
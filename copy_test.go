@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go/parser"
+	"testing"
+)
+
+func TestShared(t *testing.T) {
+	for typ, expected := range map[string]bool{
+		"[]string":       true,
+		"map[string]int": true,
+		"[3]int":         false,
+		"*[]int":         false,
+		"string":         false,
+	} {
+		expr, err := parser.ParseExpr(typ)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, shared(expr), typ)
+	}
+}
+
+func TestBuildCopies(t *testing.T) {
+	t.Run("arrays", func(t *testing.T) {
+		fields := []genField{
+			{name: "Tags", typ: "string", array: true, copy: true},
+			{name: "Matrix", typ: "[]int", array: true, copy: true},
+			{name: "Shared", typ: "string", array: true},
+		}
+		expected := "if Tags != nil {\nc := make([]string, len(Tags))\ncopy(c, Tags)\nTags = c\n}\n" +
+			"if Matrix != nil {\nc := make([][]int, len(Matrix))\nfor i, v := range Matrix {\n" +
+			"if v != nil {\nc2 := make([]int, len(v))\ncopy(c2, v)\nc[i] = c2\n}\n}\nMatrix = c\n}\n"
+		assert.Equal(t, expected, buildCopies("Sample", fields))
+	})
+
+	t.Run("maps", func(t *testing.T) {
+		fields := []genField{
+			{name: "Attrs", typ: "map[string][]string", mapped: true, optional: true, copy: true},
+		}
+		expected := "if Attrs != nil {\nc := make(map[string][]string, len(Attrs))\nfor k, v := range Attrs {\nc[k] = v\n" +
+			"if v != nil {\nc2 := make([]string, len(v))\ncopy(c2, v)\nc[k] = c2\n}\n}\nAttrs = c\n}\n"
+		assert.Equal(t, expected, buildCopies("Sample", fields))
+	})
+
+	t.Run("not an array or map", func(t *testing.T) {
+		assert.Panics(t, func() {
+			buildCopies("Sample", []genField{{name: "Name", typ: "string", copy: true}})
+		})
+	})
+}
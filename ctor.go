@@ -0,0 +1,80 @@
+package main
+
+import (
+	"log"
+)
+
+// genCtor is a named constructor of a struct, with the fields tagged fmgen:"ctor=profile" required
+type genCtor struct {
+	profile  string
+	funcName string
+}
+
+// ctors returns the named constructors of the struct in the order they are declared with fmgen:ctor Profile
+// name=NewFunc, followed by the profiles only found in field tags. The function defaults to New<Profile><Struct>
+func (g genStruct) ctors() []genCtor {
+	var result []genCtor
+	seen := make(map[string]bool)
+	add := func(profile, funcName string) {
+		if funcName == "" {
+			funcName = companionName(g, capitalize(profile)+capitalize(g.name))
+		}
+		if funcName == g.factoryName() {
+			log.Panicf("constructor [%s] in struct [%s] can't be named %s", profile, g.name, funcName)
+		}
+		seen[profile] = true
+		result = append(result, genCtor{profile: profile, funcName: funcName})
+	}
+
+	for _, d := range findDirectives(g.comment, "ctor") {
+		if len(d.args) == 0 {
+			log.Panicf("constructor in struct [%s] is missing a name", g.name)
+		}
+		profile := d.args[0]
+		if seen[profile] {
+			log.Panicf("constructor [%s] is declared more than once in struct [%s]", profile, g.name)
+		}
+
+		var funcName string
+		for _, arg := range d.args[1:] {
+			name, value, ok := splitAssignment(arg)
+			if !ok || name != "name" || value == "" {
+				log.Panicf("invalid constructor [%s] argument [%s] in struct [%s], expected name=NewFunc", profile, arg, g.name)
+			}
+			funcName = value
+		}
+		add(profile, funcName)
+	}
+
+	for _, f := range g.fields {
+		for _, profile := range f.ctors {
+			if !seen[profile] {
+				add(profile, "")
+			}
+		}
+	}
+	return result
+}
+
+// ctorFields returns the fields of the named constructor with its policies applied, fields tagged with the profile
+// are required while fields tagged with other profiles are optional
+func (g genStruct) ctorFields(profile string) []genField {
+	fields := g.policyFields()
+	for i, f := range fields {
+		if len(f.ctors) == 0 || f.skip || f.gen != "" {
+			continue
+		}
+
+		f.optional = true
+		for _, p := range f.ctors {
+			if p == profile {
+				f.optional = false
+			}
+		}
+		if f.optional && f.flatten != "" {
+			log.Panicf("%s field [%s] in struct [%s] must be required in constructor [%s]", f.flatten, f.name, g.name, profile)
+		}
+		fields[i] = f
+	}
+	return fields
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCtors(t *testing.T) {
+	s := genStruct{
+		name:    "Sample",
+		comment: &genComment{value: "Sample a sample\nfmgen:ctor Minimal name=NewMinimal\nfmgen:ctor Full"},
+		fields: []genField{
+			{name: "ID", typ: "string"},
+			{name: "Name", typ: "string", ctors: []string{"Full"}},
+			{name: "Email", typ: "string", optional: true, ctors: []string{"Full", "Contact"}},
+		},
+	}
+	expected := []genCtor{
+		{profile: "Minimal", funcName: "NewMinimal"},
+		{profile: "Full", funcName: "NewFullSample"},
+		{profile: "Contact", funcName: "NewContactSample"},
+	}
+	assert.Equal(t, expected, s.ctors())
+
+	t.Run("declared name", func(t *testing.T) {
+		s := genStruct{name: "Sample", funcName: "CreateSample", fields: []genField{{name: "Name", typ: "string", ctors: []string{"Full"}}}}
+		assert.Equal(t, []genCtor{{profile: "Full", funcName: "CreateFullSample"}}, s.ctors())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Panics(t, func() {
+			genStruct{name: "Sample", comment: &genComment{value: "fmgen:ctor Full\nfmgen:ctor Full"}}.ctors()
+		})
+		assert.Panics(t, func() {
+			genStruct{name: "Sample", comment: &genComment{value: "fmgen:ctor Full func=NewFull"}}.ctors()
+		})
+		assert.Panics(t, func() {
+			genStruct{name: "Sample", comment: &genComment{value: "fmgen:ctor Full name=NewSample"}}.ctors()
+		})
+	})
+}
+
+func TestCtorFields(t *testing.T) {
+	s := genStruct{
+		name: "Sample",
+		fields: []genField{
+			{name: "ID", typ: "string"},
+			{name: "Name", typ: "string", ctors: []string{"Full"}},
+			{name: "Email", typ: "string", optional: true, ctors: []string{"Full"}},
+		},
+	}
+
+	minimal := s.ctorFields("Minimal")
+	assert.False(t, minimal[0].optional)
+	assert.True(t, minimal[1].optional)
+	assert.True(t, minimal[2].optional)
+
+	full := s.ctorFields("Full")
+	assert.False(t, full[1].optional)
+	assert.False(t, full[2].optional)
+	assert.False(t, s.fields[1].optional)
+	assert.True(t, s.fields[2].optional)
+
+	assert.Panics(t, func() {
+		genStruct{name: "Sample", fields: []genField{{name: "Home", typ: "Address", flatten: "flatten", ctors: []string{"Full"}}}}.ctorFields("Minimal")
+	})
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// method generated on each defined type to validate its value
+const validMethod = "IsValid"
+
+func formatParseName(in string) string {
+	return "Parse" + capitalize(in)
+}
+
+func formatValuesName(in string) string {
+	return capitalize(in) + "Values"
+}
+
+// definedFuncs returns the names of the functions generated for the type
+func definedFuncs(t genType) []string {
+	names := []string{formatStructName(t.name), formatParseName(t.name)}
+	if t.enum() {
+		names = append(names, formatValuesName(t.name))
+	}
+	return names
+}
+
+// parseNumber returns the expression parsing the string s for the underlying type
+func parseNumber(underlying string) string {
+	switch underlying {
+	case "int":
+		return "strconv.ParseInt(s, 10, 0)"
+	case "int8", "int16", "int32", "int64":
+		return fmt.Sprintf("strconv.ParseInt(s, 10, %s)", strings.TrimPrefix(underlying, "int"))
+	case "rune":
+		return "strconv.ParseInt(s, 10, 32)"
+	case "uint":
+		return "strconv.ParseUint(s, 10, 0)"
+	case "uint8", "uint16", "uint32", "uint64":
+		return fmt.Sprintf("strconv.ParseUint(s, 10, %s)", strings.TrimPrefix(underlying, "uint"))
+	case "byte":
+		return "strconv.ParseUint(s, 10, 8)"
+	case "float32":
+		return "strconv.ParseFloat(s, 32)"
+	default:
+		return "strconv.ParseFloat(s, 64)"
+	}
+}
+
+// buildValidConditions returns the conditions which must all be true for a value of the type to be valid
+func buildValidConditions(t genType) []string {
+	var conditions []string
+	min, max := t.valueRange()
+	if len(t.consts) > 0 {
+		var cases []string
+		for _, c := range t.consts {
+			cases = append(cases, fmt.Sprintf("v == %s", c))
+		}
+		condition := strings.Join(cases, " || ")
+		if len(cases) > 1 && (min != "" || max != "") {
+			condition = fmt.Sprintf("(%s)", condition)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if min != "" {
+		conditions = append(conditions, fmt.Sprintf("v >= %s", min))
+	}
+	if max != "" {
+		conditions = append(conditions, fmt.Sprintf("v <= %s", max))
+	}
+	return conditions
+}
+
+func writeDefinedType(w io.Writer, t genType) {
+	min, max := t.valueRange()
+	if t.underlying == "string" && (min != "" || max != "") {
+		log.Panicf("unable to generate %s, fmgen:range is only supported for numeric types", t.name)
+	}
+	if t.enum() && len(t.consts) == 0 {
+		log.Panicf("unable to generate %s, fmgen:enum requires constants declared with the type", t.name)
+	}
+
+	fmFuncName := formatStructName(t.name)
+	param, zero, verb := "v", "0", "%v"
+	if t.underlying == "string" {
+		param, zero, verb = "s", `""`, "%q"
+	}
+
+	// constructor
+	fmt.Fprintf(w, "// %s generated factory method for %s, returns an error when the value is not valid\n", fmFuncName, t.name)
+	fmt.Fprintf(w, "func %s(%s %s) (%s, error) {\n", fmFuncName, param, t.underlying, t.name)
+	fmt.Fprintf(w, "result := %s(%s)\n", t.name, param)
+	fmt.Fprintf(w, "if !result.%s() {\nreturn %s, fmt.Errorf(\"invalid %s %s\", %s)\n}\n", validMethod, zero, t.name, verb, param)
+	fmt.Fprintln(w, "return result, nil")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+
+	// parse the text form of the value
+	parseName := formatParseName(t.name)
+	fmt.Fprintf(w, "// %s parses the text form of %s, returns an error when the value is not valid\n", parseName, t.name)
+	fmt.Fprintf(w, "func %s(s string) (%s, error) {\n", parseName, t.name)
+	if t.underlying == "string" {
+		fmt.Fprintf(w, "return %s(s)\n", fmFuncName)
+	} else {
+		parse := parseNumber(t.underlying)
+		fmt.Fprintf(w, "v, err := %s\n", parse)
+		fmt.Fprintf(w, "if err != nil {\nreturn 0, fmt.Errorf(\"invalid %s %%q - %%w\", s, err)\n}\n", t.name)
+		fmt.Fprintf(w, "return %s(%s(v))\n", fmFuncName, t.underlying)
+	}
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+
+	// validation
+	fmt.Fprintf(w, "// %s returns true when the %s is valid\n", validMethod, t.name)
+	fmt.Fprintf(w, "func (v %s) %s() bool {\n", t.name, validMethod)
+	fmt.Fprintf(w, "return %s\n", strings.Join(buildValidConditions(t), " && "))
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+
+	// all declared constants
+	if len(t.consts) > 0 {
+		valuesName := formatValuesName(t.name)
+		fmt.Fprintf(w, "// %s returns all declared %s constants\n", valuesName, t.name)
+		fmt.Fprintf(w, "func %s() []%s {\n", valuesName, t.name)
+		fmt.Fprintf(w, "return []%s{%s}\n", t.name, strings.Join(t.consts, ", "))
+		fmt.Fprintln(w, "}")
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFormatParseName(t *testing.T) {
+	assert.Equal(t, "ParseStatus", formatParseName("status"))
+	assert.Equal(t, "StatusValues", formatValuesName("status"))
+}
+
+func TestParseNumber(t *testing.T) {
+	assert.Equal(t, "strconv.ParseInt(s, 10, 0)", parseNumber("int"))
+	assert.Equal(t, "strconv.ParseInt(s, 10, 16)", parseNumber("int16"))
+	assert.Equal(t, "strconv.ParseInt(s, 10, 32)", parseNumber("rune"))
+	assert.Equal(t, "strconv.ParseUint(s, 10, 8)", parseNumber("byte"))
+	assert.Equal(t, "strconv.ParseFloat(s, 32)", parseNumber("float32"))
+}
+
+func TestBuildValidConditions(t *testing.T) {
+	t.Run("constants", func(t *testing.T) {
+		typ := genType{name: "Status", underlying: "string", consts: []string{"StatusActive", "StatusInactive"}}
+		assert.Equal(t, []string{"v == StatusActive || v == StatusInactive"}, buildValidConditions(typ))
+	})
+
+	t.Run("range", func(t *testing.T) {
+		typ := genType{name: "Port", underlying: "int", comment: &genComment{value: "fmgen:range min=1 max=65535"}}
+		assert.Equal(t, []string{"v >= 1", "v <= 65535"}, buildValidConditions(typ))
+	})
+
+	t.Run("constants and range", func(t *testing.T) {
+		typ := genType{
+			name:       "Level",
+			underlying: "int",
+			consts:     []string{"LevelLow", "LevelHigh"},
+			comment:    &genComment{value: "fmgen:range max=10"},
+		}
+		assert.Equal(t, []string{"(v == LevelLow || v == LevelHigh)", "v <= 10"}, buildValidConditions(typ))
+	})
+}
+
+func TestWriteDefinedType(t *testing.T) {
+	t.Run("string range", func(t *testing.T) {
+		typ := genType{name: "Status", underlying: "string", comment: &genComment{value: "fmgen:range min=1"}}
+		assert.Panics(t, func() {
+			writeDefinedType(&bytes.Buffer{}, typ)
+		})
+	})
+
+	t.Run("enum without constants", func(t *testing.T) {
+		typ := genType{name: "Status", underlying: "string", comment: &genComment{value: "fmgen:enum"}}
+		assert.Panics(t, func() {
+			writeDefinedType(&bytes.Buffer{}, typ)
+		})
+	})
+}
+
+func TestDefinedFuncs(t *testing.T) {
+	assert.Equal(t, []string{"NewPort", "ParsePort"}, definedFuncs(genType{name: "Port"}))
+	assert.Equal(t, []string{"NewStatus", "ParseStatus", "StatusValues"}, definedFuncs(genType{name: "Status", comment: &genComment{value: "fmgen:enum"}}))
+}
+
+func TestWritePackageFileDefinedTypes(t *testing.T) {
+	buf := bytes.Buffer{}
+	genTypes := []genType{
+		{
+			name:       "Status",
+			underlying: "string",
+			consts:     []string{"StatusActive", "StatusInactive"},
+			comment:    &genComment{value: "fmgen:enum"},
+		},
+		{
+			name:       "Port",
+			underlying: "uint16",
+			comment:    &genComment{value: "fmgen:range min=1"},
+		},
+		{
+			name:       "ID",
+			underlying: "string",
+		},
+		{
+			name:       "Level",
+			underlying: "int",
+			consts:     []string{"LevelLow"},
+			comment:    &genComment{value: "fmgen:enum"},
+			declared:   []string{"ParseLevel"},
+		},
+	}
+
+	expected := `// Code generated by "fmgen". DO NOT EDIT.
+package testdata
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// NewStatus generated factory method for Status, returns an error when the value is not valid
+func NewStatus(s string) (Status, error) {
+	result := Status(s)
+	if !result.IsValid() {
+		return "", fmt.Errorf("invalid Status %q", s)
+	}
+	return result, nil
+}
+
+// ParseStatus parses the text form of Status, returns an error when the value is not valid
+func ParseStatus(s string) (Status, error) {
+	return NewStatus(s)
+}
+
+// IsValid returns true when the Status is valid
+func (v Status) IsValid() bool {
+	return v == StatusActive || v == StatusInactive
+}
+
+// StatusValues returns all declared Status constants
+func StatusValues() []Status {
+	return []Status{StatusActive, StatusInactive}
+}
+
+// NewPort generated factory method for Port, returns an error when the value is not valid
+func NewPort(v uint16) (Port, error) {
+	result := Port(v)
+	if !result.IsValid() {
+		return 0, fmt.Errorf("invalid Port %v", v)
+	}
+	return result, nil
+}
+
+// ParsePort parses the text form of Port, returns an error when the value is not valid
+func ParsePort(s string) (Port, error) {
+	v, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Port %q - %w", s, err)
+	}
+	return NewPort(uint16(v))
+}
+
+// IsValid returns true when the Port is valid
+func (v Port) IsValid() bool {
+	return v >= 1
+}
+`
+
+	writePackageFile(&buf, "testdata", []string{}, nil, nil, genTypes)
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+)
+
+const directivePrefix = tagName + ":"
+
+// directive is a single fmgen instruction found in a comment, e.g. fmgen:trait admin Role="admin"
+type directive struct {
+	name string
+	args []string
+}
+
+// splitArgs splits a directive on whitespace, keeping quoted values together
+func splitArgs(in string) []string {
+	var args []string
+	var sb strings.Builder
+	var quote rune
+
+	for _, c := range in {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			sb.WriteRune(c)
+		case c == '"' || c == '`':
+			quote = c
+			sb.WriteRune(c)
+		case c == ' ' || c == '\t':
+			if sb.Len() > 0 {
+				args = append(args, sb.String())
+				sb.Reset()
+			}
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	if sb.Len() > 0 {
+		args = append(args, sb.String())
+	}
+
+	return args
+}
+
+// parseDirectives finds every fmgen directive within a comment, a directive continues until the end of the line
+func parseDirectives(comment string) []directive {
+	var directives []directive
+
+	for _, line := range strings.Split(comment, "\n") {
+		idx := strings.Index(line, directivePrefix)
+		if idx < 0 {
+			continue
+		}
+
+		args := splitArgs(line[idx+len(directivePrefix):])
+		if len(args) == 0 {
+			continue
+		}
+
+		// a single argument may be assigned to the name, e.g. fmgen:name=CreateSample
+		name, rest := args[0], args[1:]
+		if key, value, ok := splitAssignment(name); ok {
+			name, rest = key, append([]string{value}, rest...)
+		}
+
+		directives = append(directives, directive{
+			name: name,
+			args: rest,
+		})
+	}
+
+	return directives
+}
+
+// findDirectives returns all directives within the comment matching the name
+func findDirectives(comment *genComment, name string) []directive {
+	if comment == nil {
+		return nil
+	}
+
+	var result []directive
+	for _, d := range parseDirectives(comment.value) {
+		if strings.EqualFold(d.name, name) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// splitAssignment splits an argument such as Role="admin" into the name and value
+func splitAssignment(arg string) (string, string, bool) {
+	idx := strings.Index(arg, "=")
+	if idx <= 0 {
+		return "", "", false
+	}
+	return arg[:idx], arg[idx+1:], true
+}
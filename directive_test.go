@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	assert.Equal(t, []string{"trait", "admin", `Role="admin"`, "Active=true"}, splitArgs(`trait admin  Role="admin"	Active=true`))
+	assert.Equal(t, []string{"trait", `Name="Jane Doe"`, "Tag=`a b`"}, splitArgs("trait Name=\"Jane Doe\" Tag=`a b`"))
+	assert.Nil(t, splitArgs("   "))
+}
+
+func TestParseDirectives(t *testing.T) {
+	t.Run("multiple lines", func(t *testing.T) {
+		comment := "Sample demo struct\nfmgen:trait admin Role=\"admin\"\nfmgen:trait guest\n"
+		expected := []directive{
+			{name: "trait", args: []string{"admin", `Role="admin"`}},
+			{name: "trait", args: []string{"guest"}},
+		}
+		assert.Equal(t, expected, parseDirectives(comment))
+	})
+
+	t.Run("inline directive", func(t *testing.T) {
+		expected := []directive{{name: "-", args: []string{}}}
+		assert.Equal(t, expected, parseDirectives("Sample demo struct, fmgen:-"))
+	})
+
+	t.Run("assigned name", func(t *testing.T) {
+		expected := []directive{{name: "name", args: []string{"CreateSample"}}}
+		assert.Equal(t, expected, parseDirectives("Sample demo struct\nfmgen:name=CreateSample"))
+	})
+
+	t.Run("no directives", func(t *testing.T) {
+		assert.Nil(t, parseDirectives("Sample demo struct\nfmgen:\n"))
+	})
+}
+
+func TestFindDirectives(t *testing.T) {
+	comment := &genComment{value: "Sample\nfmgen:Trait admin\nfmgen:pool\n"}
+	assert.Equal(t, []directive{{name: "Trait", args: []string{"admin"}}}, findDirectives(comment, "trait"))
+	assert.Nil(t, findDirectives(comment, "sealed"))
+	assert.Nil(t, findDirectives(nil, "trait"))
+}
+
+func TestSplitAssignment(t *testing.T) {
+	name, value, ok := splitAssignment(`Role="a=b"`)
+	assert.True(t, ok)
+	assert.Equal(t, "Role", name)
+	assert.Equal(t, `"a=b"`, value)
+
+	_, _, ok = splitAssignment("admin")
+	assert.False(t, ok)
+
+	_, _, ok = splitAssignment("=true")
+	assert.False(t, ok)
+}
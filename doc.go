@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const deprecatedPrefix = "Deprecated:"
+
+// paragraphs splits the comment into paragraphs, fmgen directives are removed
+func paragraphs(comment string) []string {
+	var result, lines []string
+	flush := func() {
+		if len(lines) > 0 {
+			result = append(result, strings.Join(lines, "\n"))
+			lines = nil
+		}
+	}
+
+	for _, line := range strings.Split(comment, "\n") {
+		if idx := strings.Index(line, directivePrefix); idx >= 0 {
+			line = strings.TrimRight(line[:idx], " ,")
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return result
+}
+
+// splitDeprecated returns the paragraphs of the comment along with its Deprecated: paragraph, if any
+func splitDeprecated(comment string) ([]string, string) {
+	var doc []string
+	var deprecated string
+	for _, p := range paragraphs(comment) {
+		if strings.HasPrefix(p, deprecatedPrefix) {
+			deprecated = p
+			continue
+		}
+		doc = append(doc, p)
+	}
+	return doc, deprecated
+}
+
+// commentLines returns the text as comment lines
+func commentLines(text string) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		sb.WriteString("// " + line + "\n")
+	}
+	return sb.String()
+}
+
+// paramDoc returns the description of a param from the doc of its field, noting when it is optional or deprecated
+func paramDoc(p genParam) string {
+	doc, deprecated := splitDeprecated(p.field.doc)
+	desc := strings.TrimSuffix(strings.Join(strings.Fields(strings.Join(doc, " ")), " "), ".")
+
+	var notes []string
+	switch {
+	case p.field.optional && p.field.init:
+		notes = append(notes, "optional, nil is replaced with an empty value")
+	case p.field.optional:
+		notes = append(notes, fmt.Sprintf("optional, nil leaves %s unset", p.field.name))
+	}
+	if p.field.conv != "" {
+		notes = append(notes, fmt.Sprintf("converted with %s", p.field.conv))
+	}
+	if deprecated != "" {
+		reason := strings.TrimSuffix(strings.Join(strings.Fields(strings.TrimPrefix(deprecated, deprecatedPrefix)), " "), ".")
+		if reason == "" {
+			notes = append(notes, "deprecated")
+		} else {
+			notes = append(notes, "deprecated: "+reason)
+		}
+	}
+
+	switch {
+	case len(notes) == 0:
+		return desc
+	case desc == "":
+		return strings.Join(notes, "; ")
+	}
+	return desc + " (" + strings.Join(notes, "; ") + ")"
+}
+
+// buildDoc returns the doc comment following the first line of a factory method, with the summary of the struct, the
+// description of each param and the source of the struct. A deprecated struct deprecates its factory methods as well
+func buildDoc(s genStruct, fields []genField) string {
+	var sb strings.Builder
+
+	var deprecated string
+	if s.comment != nil {
+		var doc []string
+		doc, deprecated = splitDeprecated(s.comment.value)
+		if len(doc) > 0 {
+			// a summary without punctuation would be formatted as a heading
+			summary := doc[0]
+			if !strings.HasSuffix(summary, ".") {
+				summary += "."
+			}
+			sb.WriteString("//\n" + commentLines(summary))
+		}
+	}
+
+	params := factoryParams(fields)
+	if len(params) > 0 {
+		sb.WriteString("//\n// Parameters:\n")
+		for _, p := range params {
+			if desc := paramDoc(p); desc != "" {
+				sb.WriteString(fmt.Sprintf("//   - %s: %s\n", p.name, desc))
+			} else {
+				sb.WriteString(fmt.Sprintf("//   - %s\n", p.name))
+			}
+		}
+	}
+
+	if s.file != "" {
+		sb.WriteString(fmt.Sprintf("//\n// Generated from %s:%d\n", s.file, s.lineNum))
+	}
+	if deprecated != "" {
+		sb.WriteString("//\n" + commentLines(deprecated))
+	}
+	return sb.String()
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParagraphs(t *testing.T) {
+	comment := "Sample holds a sample\ntaken somewhere fmgen:pool\n\nfmgen:ctor Minimal\nMore details\n"
+	assert.Equal(t, []string{"Sample holds a sample\ntaken somewhere", "More details"}, paragraphs(comment))
+	assert.Nil(t, paragraphs("fmgen:-\n"))
+}
+
+func TestSplitDeprecated(t *testing.T) {
+	doc, deprecated := splitDeprecated("Code of the sample\n\nDeprecated: codes are no longer used.\n")
+	assert.Equal(t, []string{"Code of the sample"}, doc)
+	assert.Equal(t, "Deprecated: codes are no longer used.", deprecated)
+
+	doc, deprecated = splitDeprecated("Code of the sample\n")
+	assert.Equal(t, []string{"Code of the sample"}, doc)
+	assert.Empty(t, deprecated)
+}
+
+func TestParamDoc(t *testing.T) {
+	assert.Empty(t, paramDoc(genParam{name: "name", field: genField{name: "Name"}}))
+	assert.Equal(t, "Name of the sample", paramDoc(genParam{name: "name", field: genField{name: "Name", doc: "Name of the\nsample."}}))
+	assert.Equal(t, "optional, nil leaves Age unset", paramDoc(genParam{name: "age", field: genField{name: "Age", optional: true}}))
+	assert.Equal(t, "optional, nil is replaced with an empty value", paramDoc(genParam{name: "tags", field: genField{name: "Tags", optional: true, init: true}}))
+	assert.Equal(t, "Legacy code (optional, nil leaves Code unset; deprecated: codes are no longer used)",
+		paramDoc(genParam{name: "code", field: genField{name: "Code", optional: true, doc: "Legacy code\n\nDeprecated: codes are no longer used."}}))
+	assert.Equal(t, "converted with uuid.Parse", paramDoc(genParam{name: "id", field: genField{name: "ID", conv: "uuid.Parse"}}))
+}
+
+func TestBuildDoc(t *testing.T) {
+	s := genStruct{
+		name:    "Sample",
+		file:    "sample.go",
+		lineNum: 12,
+		comment: &genComment{value: "Sample holds a sample\n\nMore details\n\nDeprecated: use Reading instead.\nfmgen:pool\n"},
+		fields: []genField{
+			{name: "Name", typ: "string", doc: "Name of the sample", param: "name"},
+			{name: "Age", typ: "int", optional: true, param: "age"},
+			{name: "ID", typ: "string", skip: true},
+		},
+	}
+	expected := "//\n// Sample holds a sample.\n//\n// Parameters:\n//   - name: Name of the sample\n//   - age: optional, nil leaves Age unset\n" +
+		"//\n// Generated from sample.go:12\n//\n// Deprecated: use Reading instead.\n"
+	assert.Equal(t, expected, buildDoc(s, s.fields))
+
+	assert.Empty(t, buildDoc(genStruct{name: "Empty"}, nil))
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"log"
+	"strings"
+	"unicode"
+)
+
+var exampleImports = []string{`"fmt"`, `"time"`}
+
+func formatExampleName(s genStruct) string {
+	return "Example" + s.factoryName()
+}
+
+// lowerFirst returns the name with the first letter lower case, for use as a local variable
+func lowerFirst(in string) string {
+	result := string(unicode.ToLower(rune(in[0]))) + in[1:]
+	if token.IsKeyword(result) {
+		return result + "Value"
+	}
+	return result
+}
+
+// exampleValue returns a realistic literal value for a single element of the field along with how it will be printed,
+// false is returned when the type is not supported
+func exampleValue(f genField) (string, string, bool) {
+	lower := strings.ToLower(f.name)
+
+	switch f.typ {
+	case "string":
+		var value string
+		switch {
+		case strings.Contains(lower, "email"):
+			value = "jane.doe@example.com"
+		case strings.Contains(lower, "url"):
+			value = "https://example.com"
+		case strings.Contains(lower, "phone"):
+			value = "555-0100"
+		case isIDField(f.name):
+			value = "3f2a9c1e"
+		case strings.Contains(lower, "name"):
+			value = "Jane Doe"
+		default:
+			value = lower
+		}
+		return fmt.Sprintf("%q", value), value, true
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte":
+		value := "10"
+		switch {
+		case isIDField(f.name):
+			value = "1"
+		case strings.Contains(lower, "age"):
+			value = "42"
+		}
+		if f.typ == "int" {
+			return value, value, true
+		}
+		return fmt.Sprintf("%s(%s)", f.typ, value), value, true
+	case "float32", "float64":
+		if f.typ == "float64" {
+			return "9.99", "9.99", true
+		}
+		return "float32(9.99)", "9.99", true
+	case "bool":
+		return "true", "true", true
+	case "time.Time":
+		return "time.Date(2021, time.October, 1, 12, 0, 0, 0, time.UTC)", "2021-10-01T12:00:00Z", true
+	case "time.Duration":
+		return "90 * time.Second", "1m30s", true
+	}
+
+	return "", "", false
+}
+
+// buildExampleArg returns the argument passed to the factory method for the parameter, along with any statements
+// declaring variables it requires and how the resulting field will be printed. The argument is never nil when set is
+// true.
+func buildExampleArg(p genParam, set bool) (string, string, string, bool) {
+	f := p.field
+	varName := lowerFirst(p.name)
+
+	value, output, ok := exampleValue(f)
+	switch {
+	case !ok && f.optional && !set:
+		return "nil", "", "", false
+	case !ok && (nilChecked(f) || set):
+		// checked and set fields must not be nil
+		if v, ok := nilCheckedValue(f); ok {
+			return varName, fmt.Sprintf("%s := %s\n", varName, v), "", false
+		}
+		return "&" + varName, fmt.Sprintf("var %s %s\n", varName, valueType(f)), "", false
+	case !ok:
+		return varName, fmt.Sprintf("var %s %s\n", varName, valueType(f)), "", false
+	case f.array && f.ptr:
+		// pointer addresses are not predictable, so arrays of pointers are not printed
+		return varName, fmt.Sprintf("%s := %s\n%s := []*%s{&%s}\n", varName+"Element", value, varName, f.typ, varName+"Element"), "", false
+	case f.array:
+		return fmt.Sprintf("[]%s{%s}", f.typ, value), "", fmt.Sprintf("[%s]", output), true
+	case f.optional:
+		return "&" + varName, fmt.Sprintf("%s := %s\n", varName, value), output, true
+	default:
+		return value, "", output, true
+	}
+}
+
+// buildExamplePrint returns the statement printing the field of the result
+func buildExamplePrint(result string, f genField) string {
+	got := fmt.Sprintf("%s.%s", result, f.name)
+	switch {
+	case f.typ == "time.Time" && !f.array:
+		return fmt.Sprintf("fmt.Println(%s.Format(time.RFC3339))\n", got)
+	case f.ptr && !f.array:
+		return fmt.Sprintf("fmt.Println(*%s)\n", got)
+	default:
+		return fmt.Sprintf("fmt.Println(%s)\n", got)
+	}
+}
+
+func writeExample(w io.Writer, s genStruct) {
+	fmFuncName := s.factoryName()
+	if reason, ok := untestable(s); ok {
+		log.Printf("skipping example for struct [%s], %s\n", s.name, reason)
+		return
+	}
+
+	result := lowerFirst(s.name)
+	for _, p := range factoryParams(s.fields) {
+		// avoid shadowing a parameter with the result
+		if lowerFirst(p.name) == result {
+			result += "Result"
+		}
+	}
+
+	var setup, prints, output strings.Builder
+	var args []string
+	printed := make(map[string]string)
+	constrained := satisfyingFields(s.fields)
+	for _, p := range factoryParams(s.fields) {
+		set, isConstrained := constrained[p.field.name]
+		if isConstrained && !set {
+			// constrained parameters which aren't set to satisfy the constraints are left nil
+			args = append(args, "nil")
+			continue
+		}
+
+		arg, vars, out, ok := buildExampleArg(p, set)
+		args = append(args, arg)
+		setup.WriteString(vars)
+		// normalized fields don't print the value passed in, so they are left out of the output
+		if ok && len(p.field.normalize) == 0 {
+			printed[p.field.name] = out
+		}
+	}
+
+	// print the fields in the order they are declared, a String method isn't used as its output can't be predicted
+	for _, f := range s.fields {
+		if out, ok := printed[f.name]; ok {
+			prints.WriteString(buildExamplePrint(result, f))
+			output.WriteString(fmt.Sprintf("// %s\n", out))
+		}
+	}
+
+	fmt.Fprintf(w, "func %s() {\n", formatExampleName(s))
+	fmt.Fprint(w, setup.String())
+	call := fmt.Sprintf("%s(%s)", fmFuncName, strings.Join(args, ", "))
+	if prints.Len() == 0 {
+		if returnsError(s.fields) {
+			fmt.Fprintf(w, "if _, err := %s; err != nil {\nfmt.Println(err)\n}\n", call)
+		} else {
+			fmt.Fprintf(w, "_ = %s\n", call)
+		}
+		fmt.Fprintln(w, "}")
+		fmt.Fprintln(w)
+		return
+	}
+
+	fmt.Fprint(w, buildCall(s.fields, result, call, "fmt.Println(err)\nreturn\n"))
+	fmt.Fprint(w, prints.String())
+	fmt.Fprintln(w, "// Output:")
+	fmt.Fprint(w, output.String())
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+}
+
+func writeExamplesFile(w io.Writer, pkg string, pkgImports []string, structs []genStruct) {
+	log.Printf("generating factory method examples file for package [%s]", pkg)
+
+	writeGoFile(w, examplesFileName, pkg, mergeImports(pkgImports, exampleImports...), func(buf io.Writer) {
+		for _, s := range structs {
+			writeExample(buf, s)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFormatExampleName(t *testing.T) {
+	assert.Equal(t, "ExampleNewGenStruct", formatExampleName(genStruct{name: "genStruct"}))
+	assert.Equal(t, "ExampleNewTest", formatExampleName(genStruct{name: "Test"}))
+}
+
+func TestLowerFirst(t *testing.T) {
+	assert.Equal(t, "lastUpdated", lowerFirst("LastUpdated"))
+	assert.Equal(t, "typeValue", lowerFirst("Type"))
+	assert.Equal(t, "name", lowerFirst("name"))
+}
+
+func TestExampleValue(t *testing.T) {
+	value, output, ok := exampleValue(genField{name: "Email", typ: "string"})
+	assert.True(t, ok)
+	assert.Equal(t, `"jane.doe@example.com"`, value)
+	assert.Equal(t, "jane.doe@example.com", output)
+
+	value, output, ok = exampleValue(genField{name: "Age", typ: "int64"})
+	assert.True(t, ok)
+	assert.Equal(t, "int64(42)", value)
+	assert.Equal(t, "42", output)
+
+	value, output, ok = exampleValue(genField{name: "Timeout", typ: "time.Duration"})
+	assert.True(t, ok)
+	assert.Equal(t, "90 * time.Second", value)
+	assert.Equal(t, "1m30s", output)
+
+	_, _, ok = exampleValue(genField{name: "BaseURL", typ: "url.URL"})
+	assert.False(t, ok)
+}
+
+func TestBuildExampleArg(t *testing.T) {
+	t.Run("optional", func(t *testing.T) {
+		f := genField{name: "Count", typ: "int", optional: true}
+		arg, vars, output, ok := buildExampleArg(genParam{name: "Count", typ: "*int", field: f}, false)
+		assert.True(t, ok)
+		assert.Equal(t, "&count", arg)
+		assert.Equal(t, "count := 10\n", vars)
+		assert.Equal(t, "10", output)
+	})
+
+	t.Run("array", func(t *testing.T) {
+		f := genField{name: "Tags", typ: "string", array: true}
+		arg, vars, output, ok := buildExampleArg(genParam{name: "Tags", typ: "[]string", field: f}, false)
+		assert.True(t, ok)
+		assert.Equal(t, `[]string{"tags"}`, arg)
+		assert.Empty(t, vars)
+		assert.Equal(t, "[tags]", output)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		f := genField{name: "BaseURL", typ: "url.URL"}
+		arg, vars, _, ok := buildExampleArg(genParam{name: "BaseURL", typ: "url.URL", field: f}, false)
+		assert.False(t, ok)
+		assert.Equal(t, "baseURL", arg)
+		assert.Equal(t, "var baseURL url.URL\n", vars)
+	})
+
+	t.Run("set unsupported optional", func(t *testing.T) {
+		f := genField{name: "BaseURL", typ: "url.URL", optional: true, oneOf: "source"}
+		arg, vars, _, ok := buildExampleArg(genParam{name: "BaseURL", typ: "*url.URL", field: f}, true)
+		assert.False(t, ok)
+		assert.Equal(t, "&baseURL", arg)
+		assert.Equal(t, "var baseURL url.URL\n", vars)
+	})
+
+	t.Run("unsupported optional", func(t *testing.T) {
+		f := genField{name: "BaseURL", typ: "url.URL", optional: true}
+		arg, vars, _, ok := buildExampleArg(genParam{name: "BaseURL", typ: "*url.URL", field: f}, false)
+		assert.False(t, ok)
+		assert.Equal(t, "nil", arg)
+		assert.Empty(t, vars)
+	})
+}
+
+func TestBuildExamplePrint(t *testing.T) {
+	assert.Equal(t, "fmt.Println(s.Name)\n", buildExamplePrint("s", genField{name: "Name", typ: "string"}))
+	assert.Equal(t, "fmt.Println(*s.Name)\n", buildExamplePrint("s", genField{name: "Name", typ: "string", ptr: true}))
+	assert.Equal(t, "fmt.Println(s.Created.Format(time.RFC3339))\n", buildExamplePrint("s", genField{name: "Created", typ: "time.Time", ptr: true}))
+}
+
+func TestWriteExamplesFile(t *testing.T) {
+	buf := bytes.Buffer{}
+	structs := []genStruct{
+		{
+			name: "Sample",
+			fields: []genField{
+				{name: "ID", typ: "int64", skip: true},
+				{name: "Name", typ: "string"},
+				{name: "Age", typ: "int64", optional: true},
+				{name: "LastUpdated", typ: "time.Time"},
+			},
+		},
+		{
+			name: "Code",
+			fields: []genField{
+				{name: "Value", typ: "string", normalize: []string{"upper"}},
+				{name: "Label", typ: "string"},
+			},
+		},
+		{
+			name: "Source",
+			fields: []genField{
+				{name: "File", typ: "string", optional: true, oneOf: "source"},
+				{name: "URL", typ: "string", optional: true, oneOf: "source"},
+			},
+		},
+		{
+			name: "Unsupported",
+			fields: []genField{
+				{name: "BaseURL", typ: "url.URL"},
+			},
+		},
+	}
+
+	expected := `// Code generated by "fmgen". DO NOT EDIT.
+package testdata
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+func ExampleNewSample() {
+	age := int64(42)
+	sample := NewSample("Jane Doe", time.Date(2021, time.October, 1, 12, 0, 0, 0, time.UTC), &age)
+	fmt.Println(sample.Name)
+	fmt.Println(sample.Age)
+	fmt.Println(sample.LastUpdated.Format(time.RFC3339))
+	// Output:
+	// Jane Doe
+	// 42
+	// 2021-10-01T12:00:00Z
+}
+
+func ExampleNewCode() {
+	code := NewCode("value", "label")
+	fmt.Println(code.Label)
+	// Output:
+	// label
+}
+
+func ExampleNewSource() {
+	file := "file"
+	source, err := NewSource(&file, nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(source.File)
+	// Output:
+	// file
+}
+
+func ExampleNewUnsupported() {
+	var baseURL url.URL
+	_ = NewUnsupported(baseURL)
+}
+`
+
+	writeExamplesFile(&buf, "testdata", []string{`"net/url"`, `"time"`}, structs)
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+)
+
+var factoryImports = []string{`"fmt"`, `fmrand "math/rand"`, `"sync"`, `"time"`}
+
+// providers always declared by the factory along with the type they provide
+var builtinProviders = map[string]string{
+	"Now":   "time.Time",
+	"NewID": "string",
+}
+
+// factoryProviders returns the custom providers used by the structs sorted by name, along with the type each provides
+func factoryProviders(structs []genStruct) ([]string, map[string]string) {
+	types := make(map[string]string)
+	for k, v := range builtinProviders {
+		types[k] = v
+	}
+
+	var custom []string
+	for _, s := range structs {
+		for _, f := range s.fields {
+			if f.provider == "" || f.skip {
+				continue
+			}
+
+			typ := valueType(f)
+			existing, ok := types[f.provider]
+			switch {
+			case !ok:
+				types[f.provider] = typ
+				custom = append(custom, f.provider)
+			case existing != typ:
+				log.Panicf("provider [%s] of field [%s] in struct [%s] provides %s, not %s", f.provider, f.name, s.name, existing, typ)
+			}
+		}
+	}
+
+	sort.Strings(custom)
+	return custom, types
+}
+
+// buildFactoryOverrides returns the statements replacing each computed field of the result with its provider
+func buildFactoryOverrides(s genStruct) string {
+	var sb strings.Builder
+	for _, f := range s.fields {
+		if !f.skip && f.gen != "" && f.provider != "" {
+			sb.WriteString(buildAssignment(f, fmt.Sprintf("f.%s()", f.provider)))
+		}
+	}
+	return sb.String()
+}
+
+// buildFactoryArgs returns the statements calling the provider of each field filled by the factory, along with the
+// arguments passed to the factory method of the struct
+func buildFactoryArgs(s genStruct) (string, []string, []string) {
+	var sb strings.Builder
+	var params, args []string
+	for _, p := range factoryParams(s.fields) {
+		f := p.field
+		if f.provider == "" {
+			params = append(params, fmt.Sprintf("%s %s", p.name, p.typ))
+			args = append(args, p.name)
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("%s := f.%s()\n", p.name, f.provider))
+		if f.optional && !f.nilable() {
+			args = append(args, "&"+p.name)
+		} else {
+			args = append(args, p.name)
+		}
+	}
+	return sb.String(), params, args
+}
+
+func writeFactoryFile(w io.Writer, pkg string, pkgImports []string, structs []genStruct) {
+	log.Printf("generating factory file for package [%s]", pkg)
+
+	name := capitalize(*flagFactory)
+	custom, types := factoryProviders(structs)
+
+	// the factory can't be declared when the names of its fields and methods collide
+	members := map[string]bool{"Now": true, "NewID": true, "Rand": true, "mu": true, "randomID": true}
+	for _, p := range custom {
+		if members[p] {
+			log.Panicf("unable to generate %s, provider [%s] is declared more than once", name, p)
+		}
+		members[p] = true
+	}
+	for _, s := range structs {
+		if capitalize(s.name) == name {
+			log.Panicf("unable to generate %s, struct [%s] has the same name", name, s.name)
+		}
+		if members[s.factoryName()] {
+			log.Panicf("unable to generate %s, [%s] is declared more than once", name, s.factoryName())
+		}
+		members[s.factoryName()] = true
+	}
+
+	var providerFields []string
+	for _, p := range custom {
+		providerFields = append(providerFields, fmt.Sprintf("%s func() %s", p, types[p]))
+	}
+
+	writeGoFile(w, factoryFileName, pkg, mergeImports(pkgImports, factoryImports...), func(buf io.Writer) {
+		fmt.Fprintf(buf, "// %s creates structs, filling fields tagged with fmgen:\"now\", fmgen:\"id\" or fmgen:\"provider=Name\" from\n", name)
+		fmt.Fprintln(buf, "// its providers")
+		fmt.Fprintf(buf, "type %s struct {\n", name)
+		fmt.Fprintln(buf, "Now func() time.Time")
+		fmt.Fprintln(buf, "NewID func() string")
+		fmt.Fprintln(buf, "Rand *fmrand.Rand")
+		fmt.Fprint(buf, joinLines(providerFields))
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, "mu sync.Mutex")
+		fmt.Fprintln(buf, "}")
+		fmt.Fprintln(buf)
+
+		fmt.Fprintf(buf, "// %s returns a %s using the current time and random IDs, custom providers must be set before use\n", formatStructName(name), name)
+		fmt.Fprintf(buf, "func %s() *%s {\n", formatStructName(name), name)
+		fmt.Fprintf(buf, "result := &%s{\nNow: time.Now,\nRand: fmrand.New(fmrand.NewSource(time.Now().UnixNano())),\n}\n", name)
+		fmt.Fprintln(buf, "result.NewID = result.randomID")
+		fmt.Fprintln(buf, "return result")
+		fmt.Fprintln(buf, "}")
+		fmt.Fprintln(buf)
+
+		fmt.Fprintln(buf, "// randomID returns a random ID from Rand")
+		fmt.Fprintf(buf, "func (f *%s) randomID() string {\n", name)
+		fmt.Fprintln(buf, "f.mu.Lock()")
+		fmt.Fprintln(buf, "defer f.mu.Unlock()")
+		fmt.Fprintln(buf, `return fmt.Sprintf("%016x", f.Rand.Uint64())`)
+		fmt.Fprintln(buf, "}")
+		fmt.Fprintln(buf)
+
+		for _, s := range structs {
+			fmFuncName := s.factoryName()
+			values, params, args := buildFactoryArgs(s)
+
+			fmt.Fprintf(buf, "// %s generated factory method for %s using the providers of the %s\n", fmFuncName, s.name, name)
+			fmt.Fprintf(buf, "func (f *%s) %s(%s) %s {\n", name, fmFuncName, strings.Join(params, ", "), buildReturnType(s.name, s.fields))
+			fmt.Fprint(buf, values)
+			if overrides := buildFactoryOverrides(s); overrides != "" {
+				// computed fields are not passed in, so they are replaced after creating the struct
+				call := fmt.Sprintf("%s(%s)", fmFuncName, strings.Join(args, ", "))
+				fmt.Fprint(buf, buildCall(s.fields, "result", call, "return nil, err\n"))
+				fmt.Fprint(buf, overrides)
+				fmt.Fprint(buf, buildReturn(s.fields, "result"))
+			} else {
+				fmt.Fprintf(buf, "return %s(%s)\n", fmFuncName, strings.Join(args, ", "))
+			}
+			fmt.Fprintln(buf, "}")
+			fmt.Fprintln(buf)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFactoryProviders(t *testing.T) {
+	t.Run("custom providers", func(t *testing.T) {
+		structs := []genStruct{
+			{
+				name: "Sample",
+				fields: []genField{
+					{name: "ID", typ: "string", provider: "NewID"},
+					{name: "Slug", typ: "string", provider: "Slug"},
+					{name: "Code", typ: "int", provider: "Code", skip: true},
+					{name: "Region", typ: "Region", ptr: true, provider: "Region"},
+				},
+			},
+		}
+		custom, types := factoryProviders(structs)
+		assert.Equal(t, []string{"Region", "Slug"}, custom)
+		assert.Equal(t, "Region", types["Region"])
+		assert.Equal(t, "string", types["Slug"])
+		assert.Equal(t, "time.Time", types["Now"])
+	})
+
+	t.Run("mismatched types", func(t *testing.T) {
+		structs := []genStruct{
+			{
+				name: "Sample",
+				fields: []genField{
+					{name: "CreatedAt", typ: "int64", provider: "Now"},
+				},
+			},
+		}
+		assert.Panics(t, func() {
+			factoryProviders(structs)
+		})
+	})
+}
+
+func TestBuildFactoryOverrides(t *testing.T) {
+	s := genStruct{
+		name: "Sample",
+		fields: []genField{
+			{name: "ID", typ: "string", gen: "newRequestID"},
+			{name: "CreatedAt", typ: "time.Time", gen: "time.Now", provider: "Now"},
+			{name: "UpdatedAt", typ: "time.Time", ptr: true, gen: "time.Now", provider: "Now"},
+		},
+	}
+	expected := "result.CreatedAt = f.Now()\n{\nv := f.Now()\nresult.UpdatedAt = &v\n}\n"
+	assert.Equal(t, expected, buildFactoryOverrides(s))
+}
+
+func TestBuildFactoryArgs(t *testing.T) {
+	s := genStruct{
+		name: "Sample",
+		fields: []genField{
+			{name: "ID", typ: "string", provider: "NewID"},
+			{name: "Name", typ: "string"},
+			{name: "Slug", typ: "string", optional: true, provider: "Slug"},
+		},
+	}
+	values, params, args := buildFactoryArgs(s)
+	assert.Equal(t, "ID := f.NewID()\nSlug := f.Slug()\n", values)
+	assert.Equal(t, []string{"Name string"}, params)
+	assert.Equal(t, []string{"ID", "Name", "&Slug"}, args)
+}
+
+func TestWriteFactoryFile(t *testing.T) {
+	*flagFactory = "Factory"
+	defer func() {
+		*flagFactory = ""
+	}()
+
+	t.Run("name collision", func(t *testing.T) {
+		structs := []genStruct{{name: "ID"}}
+		assert.Panics(t, func() {
+			writeFactoryFile(&bytes.Buffer{}, "testdata", []string{}, structs)
+		})
+	})
+
+	t.Run("write", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		structs := []genStruct{
+			{
+				name: "Sample",
+				fields: []genField{
+					{name: "ID", typ: "string", provider: "NewID"},
+					{name: "Name", typ: "string"},
+					{name: "Slug", typ: "string", provider: "Slug"},
+					{name: "CreatedAt", typ: "time.Time", ptr: true, provider: "Now", gen: "time.Now"},
+				},
+			},
+		}
+
+		expected := `// Code generated by "fmgen". DO NOT EDIT.
+package testdata
+
+import (
+	"fmt"
+	fmrand "math/rand"
+	"sync"
+	"time"
+)
+
+// Factory creates structs, filling fields tagged with fmgen:"now", fmgen:"id" or fmgen:"provider=Name" from
+// its providers
+type Factory struct {
+	Now   func() time.Time
+	NewID func() string
+	Rand  *fmrand.Rand
+	Slug  func() string
+
+	mu sync.Mutex
+}
+
+// NewFactory returns a Factory using the current time and random IDs, custom providers must be set before use
+func NewFactory() *Factory {
+	result := &Factory{
+		Now:  time.Now,
+		Rand: fmrand.New(fmrand.NewSource(time.Now().UnixNano())),
+	}
+	result.NewID = result.randomID
+	return result
+}
+
+// randomID returns a random ID from Rand
+func (f *Factory) randomID() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return fmt.Sprintf("%016x", f.Rand.Uint64())
+}
+
+// NewSample generated factory method for Sample using the providers of the Factory
+func (f *Factory) NewSample(Name string) *Sample {
+	ID := f.NewID()
+	Slug := f.Slug()
+	result := NewSample(ID, Name, Slug)
+	{
+		v := f.Now()
+		result.CreatedAt = &v
+	}
+	return result
+}
+`
+
+		writeFactoryFile(&buf, "testdata", []string{}, structs)
+		assert.Equal(t, expected, buf.String())
+	})
+}
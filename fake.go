@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"log"
+	"strings"
+)
+
+var fakeImports = []string{`"sync"`}
+
+// methods of predeclared interfaces which may be embedded
+var builtinMethods = map[string][]genMethod{
+	"error": {{name: "Error", results: []genArg{{typ: "string"}}}},
+}
+
+func formatFakeName(in string) string {
+	return "Fake" + capitalize(in)
+}
+
+func formatFakeCallName(iface, method string) string {
+	return formatFakeName(iface) + capitalize(method) + "Call"
+}
+
+// resolveMethods returns all methods of the interface including those of embedded interfaces, false is returned when
+// an embedded interface is not declared within the package
+func resolveMethods(iface genInterface, interfaces map[string]genInterface, visited map[string]bool) ([]genMethod, bool) {
+	if visited[iface.name] {
+		return nil, true
+	}
+	visited[iface.name] = true
+
+	methods := append([]genMethod{}, iface.methods...)
+	for _, e := range iface.embeds {
+		if builtin, ok := builtinMethods[e]; ok {
+			methods = append(methods, builtin...)
+			continue
+		}
+
+		embedded, ok := interfaces[e]
+		if !ok {
+			log.Printf("unable to resolve embedded interface [%s] of interface [%s]\n", e, iface.name)
+			return nil, false
+		}
+		embeddedMethods, ok := resolveMethods(embedded, interfaces, visited)
+		if !ok {
+			return nil, false
+		}
+		methods = append(methods, embeddedMethods...)
+	}
+
+	// identical methods may be embedded more than once
+	var result []genMethod
+	seen := make(map[string]bool)
+	for _, m := range methods {
+		if !seen[m.name] {
+			seen[m.name] = true
+			result = append(result, m)
+		}
+	}
+	return result, true
+}
+
+func interfacesByName(interfaces []genInterface) map[string]genInterface {
+	result := make(map[string]genInterface)
+	for _, i := range interfaces {
+		result[i.name] = i
+	}
+	return result
+}
+
+// callFieldNames returns the names of the fields recording each parameter of the method, unnamed or duplicate
+// parameters are named by their position
+func callFieldNames(m genMethod) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for i, p := range m.params {
+		name := fmt.Sprintf("Arg%d", i)
+		if p.name != "" && p.name != "_" && !seen[capitalize(p.name)] {
+			name = capitalize(p.name)
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// buildFuncType returns the type of the function field which is called by the method
+func buildFuncType(m genMethod) string {
+	var params, results []string
+	for _, p := range m.params {
+		if p.variadic {
+			params = append(params, "..."+p.typ)
+		} else {
+			params = append(params, p.typ)
+		}
+	}
+	for _, r := range m.results {
+		results = append(results, r.typ)
+	}
+
+	switch len(results) {
+	case 0:
+		return fmt.Sprintf("func(%s)", strings.Join(params, ", "))
+	case 1:
+		return fmt.Sprintf("func(%s) %s", strings.Join(params, ", "), results[0])
+	default:
+		return fmt.Sprintf("func(%s) (%s)", strings.Join(params, ", "), strings.Join(results, ", "))
+	}
+}
+
+// fakeMembers returns the names of the fields and methods the fake declares for the method
+func fakeMembers(m genMethod) []string {
+	name := capitalize(m.name)
+	return []string{m.name, name + "Func", lowerFirst(m.name) + "Calls", name + "Calls", name + "CallCount"}
+}
+
+func writeFakeMethod(w io.Writer, fakeName, ifaceName string, m genMethod) {
+	name := capitalize(m.name)
+	callName := formatFakeCallName(ifaceName, m.name)
+	callsField := lowerFirst(m.name) + "Calls"
+
+	var params, args, callFields, callValues, results []string
+	for i, p := range m.params {
+		arg := fmt.Sprintf("arg%d", i)
+		fieldName := callFieldNames(m)[i]
+		if p.variadic {
+			params = append(params, fmt.Sprintf("%s ...%s", arg, p.typ))
+			args = append(args, arg+"...")
+			callFields = append(callFields, fmt.Sprintf("%s []%s", fieldName, p.typ))
+		} else {
+			params = append(params, fmt.Sprintf("%s %s", arg, p.typ))
+			args = append(args, arg)
+			callFields = append(callFields, fmt.Sprintf("%s %s", fieldName, p.typ))
+		}
+		callValues = append(callValues, fmt.Sprintf("%s: %s", fieldName, arg))
+	}
+	for i, r := range m.results {
+		results = append(results, fmt.Sprintf("r%d %s", i, r.typ))
+	}
+
+	fmt.Fprintf(w, "// %s records the arguments of a single call to %s\n", callName, m.name)
+	fmt.Fprintf(w, "type %s struct {\n%s}\n\n", callName, joinLines(callFields))
+
+	fmt.Fprintf(w, "// %s records the call and calls %sFunc when it is set\n", m.name, name)
+	signature := fmt.Sprintf("func (f *%s) %s(%s)", fakeName, m.name, strings.Join(params, ", "))
+	if len(results) > 0 {
+		signature += fmt.Sprintf(" (%s)", strings.Join(results, ", "))
+	}
+	fmt.Fprintf(w, "%s {\n", signature)
+	fmt.Fprintln(w, "f.mu.Lock()")
+	fmt.Fprintf(w, "f.%s = append(f.%s, %s{%s})\n", callsField, callsField, callName, strings.Join(callValues, ", "))
+	fmt.Fprintf(w, "fn := f.%sFunc\n", name)
+	fmt.Fprintln(w, "f.mu.Unlock()")
+	call := fmt.Sprintf("fn(%s)", strings.Join(args, ", "))
+	if len(results) > 0 {
+		fmt.Fprintf(w, "if fn != nil {\nreturn %s\n}\nreturn\n", call)
+	} else {
+		fmt.Fprintf(w, "if fn != nil {\n%s\n}\n", call)
+	}
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "// %sCalls returns the arguments of all calls to %s\n", name, m.name)
+	fmt.Fprintf(w, "func (f *%s) %sCalls() []%s {\n", fakeName, name, callName)
+	fmt.Fprintln(w, "f.mu.Lock()")
+	fmt.Fprintln(w, "defer f.mu.Unlock()")
+	fmt.Fprintf(w, "result := make([]%s, len(f.%s))\n", callName, callsField)
+	fmt.Fprintf(w, "copy(result, f.%s)\n", callsField)
+	fmt.Fprintln(w, "return result")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "// %sCallCount returns the number of calls to %s\n", name, m.name)
+	fmt.Fprintf(w, "func (f *%s) %sCallCount() int {\n", fakeName, name)
+	fmt.Fprintln(w, "f.mu.Lock()")
+	fmt.Fprintln(w, "defer f.mu.Unlock()")
+	fmt.Fprintf(w, "return len(f.%s)\n", callsField)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+}
+
+func writeFake(w io.Writer, iface genInterface, methods []genMethod) {
+	fakeName := formatFakeName(iface.name)
+
+	// the fake can't be declared when the names of its fields and methods collide
+	members := map[string]bool{"mu": true}
+	for _, m := range methods {
+		for _, member := range fakeMembers(m) {
+			if members[member] {
+				log.Panicf("unable to generate %s, [%s] is declared more than once", fakeName, member)
+			}
+			members[member] = true
+		}
+	}
+
+	// the factory method params are named like those of structs
+	taken := func(name string) bool {
+		return token.IsKeyword(name) || types.Universe.Lookup(name) != nil
+	}
+	used := make(map[string]string)
+
+	var funcFields, callsFields, params, values []string
+	for _, m := range methods {
+		name := capitalize(m.name)
+		param := uniqueParam(name+"Func", taken, used)
+		used[param] = m.name
+		funcFields = append(funcFields, fmt.Sprintf("%sFunc %s", name, buildFuncType(m)))
+		callsFields = append(callsFields, fmt.Sprintf("%sCalls []%s", lowerFirst(m.name), formatFakeCallName(iface.name, m.name)))
+		params = append(params, fmt.Sprintf("%s %s", param, buildFuncType(m)))
+		values = append(values, fmt.Sprintf("%sFunc: %s", name, param))
+	}
+
+	fmt.Fprintf(w, "// %s is a fake implementation of %s which records all calls, the func fields are called when set\n", fakeName, iface.name)
+	fmt.Fprintf(w, "type %s struct {\n%s\nmu sync.Mutex\n%s}\n\n", fakeName, joinLines(funcFields), joinLines(callsFields))
+
+	fmt.Fprintf(w, "var _ %s = (*%s)(nil)\n\n", iface.name, fakeName)
+
+	fmt.Fprintf(w, "// %s generated factory method for %s\n", formatStructName(fakeName), fakeName)
+	fmt.Fprintf(w, "func %s(%s) *%s {\n", formatStructName(fakeName), strings.Join(params, ", "), fakeName)
+	fmt.Fprintf(w, "return &%s{\n%s}\n", fakeName, joinLines(appendEach(values, ",")))
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+
+	for _, m := range methods {
+		writeFakeMethod(w, fakeName, iface.name, m)
+	}
+}
+
+// appendEach returns the values with the suffix appended to each
+func appendEach(values []string, suffix string) []string {
+	var result []string
+	for _, v := range values {
+		result = append(result, v+suffix)
+	}
+	return result
+}
+
+func writeFakeFile(w io.Writer, pkg string, pkgImports []string, interfaces []genInterface) {
+	log.Printf("generating fakes file for package [%s]", pkg)
+
+	byName := interfacesByName(interfaces)
+
+	writeGoFile(w, fakeFileName, pkg, mergeImports(pkgImports, fakeImports...), func(buf io.Writer) {
+		for _, i := range interfaces {
+			if i.Skip() {
+				continue
+			}
+
+			methods, ok := resolveMethods(i, byName, make(map[string]bool))
+			if !ok {
+				log.Printf("skipping fake for interface [%s], embedded interfaces could not be resolved\n", i.name)
+				continue
+			}
+			if len(methods) == 0 {
+				log.Printf("skipping fake for interface [%s], no methods are declared\n", i.name)
+				continue
+			}
+			writeFake(buf, i, methods)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFormatFakeName(t *testing.T) {
+	assert.Equal(t, "FakeIface", formatFakeName("iface"))
+	assert.Equal(t, "FakeStore", formatFakeName("Store"))
+	assert.Equal(t, "FakeStoreGetCall", formatFakeCallName("Store", "Get"))
+	assert.Equal(t, "FakeStoreResetCall", formatFakeCallName("Store", "reset"))
+}
+
+func TestResolveMethods(t *testing.T) {
+	named := genInterface{name: "Named", methods: []genMethod{{name: "Name"}}}
+	interfaces := map[string]genInterface{"Named": named}
+
+	t.Run("embedded", func(t *testing.T) {
+		iface := genInterface{
+			name:    "Store",
+			methods: []genMethod{{name: "Get"}},
+			embeds:  []string{"Named", "error"},
+		}
+		methods, ok := resolveMethods(iface, interfaces, make(map[string]bool))
+		assert.True(t, ok)
+		assert.Equal(t, []genMethod{
+			{name: "Get"},
+			{name: "Name"},
+			{name: "Error", results: []genArg{{typ: "string"}}},
+		}, methods)
+	})
+
+	t.Run("duplicate methods", func(t *testing.T) {
+		iface := genInterface{name: "Store", methods: []genMethod{{name: "Name"}}, embeds: []string{"Named"}}
+		methods, ok := resolveMethods(iface, interfaces, make(map[string]bool))
+		assert.True(t, ok)
+		assert.Equal(t, []genMethod{{name: "Name"}}, methods)
+	})
+
+	t.Run("unresolved", func(t *testing.T) {
+		iface := genInterface{name: "Reader", embeds: []string{"io.Reader"}}
+		_, ok := resolveMethods(iface, interfaces, make(map[string]bool))
+		assert.False(t, ok)
+	})
+}
+
+func TestCallFieldNames(t *testing.T) {
+	m := genMethod{
+		name: "Put",
+		params: []genArg{
+			{typ: "context.Context"},
+			{name: "key", typ: "string"},
+			{name: "_", typ: "string"},
+			{name: "Key", typ: "string"},
+		},
+	}
+	assert.Equal(t, []string{"Arg0", "Key", "Arg2", "Arg3"}, callFieldNames(m))
+}
+
+func TestBuildFuncType(t *testing.T) {
+	assert.Equal(t, "func()", buildFuncType(genMethod{name: "Run"}))
+	assert.Equal(t, "func(string, ...int) error", buildFuncType(genMethod{
+		name:    "Put",
+		params:  []genArg{{name: "key", typ: "string"}, {name: "opts", typ: "int", variadic: true}},
+		results: []genArg{{typ: "error"}},
+	}))
+	assert.Equal(t, "func(string) (string, error)", buildFuncType(genMethod{
+		name:    "Get",
+		params:  []genArg{{name: "key", typ: "string"}},
+		results: []genArg{{typ: "string"}, {name: "err", typ: "error"}},
+	}))
+}
+
+func TestWriteFake(t *testing.T) {
+	t.Run("member collision", func(t *testing.T) {
+		iface := genInterface{name: "Store"}
+		methods := []genMethod{{name: "Get"}, {name: "GetFunc"}}
+		assert.Panics(t, func() {
+			writeFake(&bytes.Buffer{}, iface, methods)
+		})
+	})
+}
+
+func TestWriteFakeFile(t *testing.T) {
+	buf := bytes.Buffer{}
+	interfaces := []genInterface{
+		{
+			name: "Store",
+			methods: []genMethod{
+				{
+					name:    "Get",
+					params:  []genArg{{name: "key", typ: "string"}, {name: "opts", typ: "int", variadic: true}},
+					results: []genArg{{typ: "string"}, {typ: "error"}},
+				},
+				{name: "reset"},
+			},
+		},
+		{
+			name:    "Skipped",
+			methods: []genMethod{{name: "Run"}},
+			comment: &genComment{value: "fmgen:-"},
+		},
+		{
+			name: "Empty",
+		},
+	}
+
+	expected := `// Code generated by "fmgen". DO NOT EDIT.
+package testdata
+
+import (
+	"sync"
+)
+
+// FakeStore is a fake implementation of Store which records all calls, the func fields are called when set
+type FakeStore struct {
+	GetFunc   func(string, ...int) (string, error)
+	ResetFunc func()
+
+	mu         sync.Mutex
+	getCalls   []FakeStoreGetCall
+	resetCalls []FakeStoreResetCall
+}
+
+var _ Store = (*FakeStore)(nil)
+
+// NewFakeStore generated factory method for FakeStore
+func NewFakeStore(getFunc func(string, ...int) (string, error), resetFunc func()) *FakeStore {
+	return &FakeStore{
+		GetFunc:   getFunc,
+		ResetFunc: resetFunc,
+	}
+}
+
+// FakeStoreGetCall records the arguments of a single call to Get
+type FakeStoreGetCall struct {
+	Key  string
+	Opts []int
+}
+
+// Get records the call and calls GetFunc when it is set
+func (f *FakeStore) Get(arg0 string, arg1 ...int) (r0 string, r1 error) {
+	f.mu.Lock()
+	f.getCalls = append(f.getCalls, FakeStoreGetCall{Key: arg0, Opts: arg1})
+	fn := f.GetFunc
+	f.mu.Unlock()
+	if fn != nil {
+		return fn(arg0, arg1...)
+	}
+	return
+}
+
+// GetCalls returns the arguments of all calls to Get
+func (f *FakeStore) GetCalls() []FakeStoreGetCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make([]FakeStoreGetCall, len(f.getCalls))
+	copy(result, f.getCalls)
+	return result
+}
+
+// GetCallCount returns the number of calls to Get
+func (f *FakeStore) GetCallCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.getCalls)
+}
+
+// FakeStoreResetCall records the arguments of a single call to reset
+type FakeStoreResetCall struct {
+}
+
+// reset records the call and calls ResetFunc when it is set
+func (f *FakeStore) reset() {
+	f.mu.Lock()
+	f.resetCalls = append(f.resetCalls, FakeStoreResetCall{})
+	fn := f.ResetFunc
+	f.mu.Unlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+// ResetCalls returns the arguments of all calls to reset
+func (f *FakeStore) ResetCalls() []FakeStoreResetCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make([]FakeStoreResetCall, len(f.resetCalls))
+	copy(result, f.resetCalls)
+	return result
+}
+
+// ResetCallCount returns the number of calls to reset
+func (f *FakeStore) ResetCallCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.resetCalls)
+}
+`
+
+	writeFakeFile(&buf, "testdata", []string{`"time"`}, interfaces)
+	assert.Equal(t, expected, buf.String())
+}
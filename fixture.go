@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+)
+
+var (
+	// math/rand is imported as fmrand so it can't collide with packages importing crypto/rand
+	fixtureImports = []string{`"fmt"`, `fmrand "math/rand"`, `"time"`}
+	fixtureNames   = []string{"Alice", "Bob", "Carol", "Dave", "Erin", "Frank"}
+)
+
+func formatFixtureName(s genStruct) string {
+	return s.factoryName() + "Fixture"
+}
+
+func structsByName(structs []genStruct) map[string]genStruct {
+	result := make(map[string]genStruct, len(structs))
+	for _, s := range structs {
+		result[s.name] = s
+	}
+	return result
+}
+
+// reachesStruct returns true when the struct from contains the struct to, either directly or through its nested fields
+func reachesStruct(from, to string, structs map[string]genStruct, visited map[string]bool) bool {
+	visited[from] = true
+	for _, f := range structs[from].fields {
+		if f.skip {
+			continue
+		}
+		if _, ok := structs[f.typ]; !ok {
+			continue
+		}
+		if f.typ == to {
+			return true
+		}
+		if !visited[f.typ] && reachesStruct(f.typ, to, structs, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func isIDField(name string) bool {
+	return strings.EqualFold(name, "id") || strings.HasSuffix(name, "ID") || strings.HasSuffix(name, "Id")
+}
+
+// randomValue returns an expression building a plausible random value for a single element of the field,
+// using the field name as a hint. false is returned when the type is not supported.
+func randomValue(f genField, rnd string) (string, bool) {
+	lower := strings.ToLower(f.name)
+
+	switch f.typ {
+	case "string":
+		switch {
+		case strings.Contains(lower, "email"):
+			return fmt.Sprintf(`fmt.Sprintf("user%%d@example.com", %s.Intn(10000))`, rnd), true
+		case strings.Contains(lower, "url"):
+			return fmt.Sprintf(`fmt.Sprintf("https://example.com/%%d", %s.Intn(10000))`, rnd), true
+		case strings.Contains(lower, "phone"):
+			return fmt.Sprintf(`fmt.Sprintf("555-%%04d", %s.Intn(10000))`, rnd), true
+		case isIDField(f.name):
+			return fmt.Sprintf(`fmt.Sprintf("%s-%%d", %s.Intn(1000000))`, lower, rnd), true
+		case strings.Contains(lower, "name"):
+			return fmt.Sprintf(`[]string{"%s"}[%s.Intn(%d)]`, strings.Join(fixtureNames, `", "`), rnd, len(fixtureNames)), true
+		default:
+			return fmt.Sprintf(`fmt.Sprintf("%s-%%d", %s.Intn(10000))`, lower, rnd), true
+		}
+	case "int", "int16", "int32", "int64", "uint", "uint16", "uint32", "uint64", "uintptr":
+		switch {
+		case isIDField(f.name):
+			return fmt.Sprintf("%s(1 + %s.Intn(100000))", f.typ, rnd), true
+		case strings.Contains(lower, "age"):
+			return fmt.Sprintf("%s(18 + %s.Intn(60))", f.typ, rnd), true
+		default:
+			return fmt.Sprintf("%s(%s.Intn(1000))", f.typ, rnd), true
+		}
+	case "int8", "uint8", "byte":
+		return fmt.Sprintf("%s(%s.Intn(100))", f.typ, rnd), true
+	case "rune":
+		return fmt.Sprintf("rune('a' + %s.Intn(26))", rnd), true
+	case "float32", "float64":
+		return fmt.Sprintf("%s(%s.Float64() * 100)", f.typ, rnd), true
+	case "bool":
+		return fmt.Sprintf("%s.Intn(2) == 1", rnd), true
+	case "time.Time":
+		return fmt.Sprintf("time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(%s.Int63n(int64(365 * 24 * time.Hour))))", rnd), true
+	case "time.Duration":
+		return fmt.Sprintf("time.Duration(%s.Int63n(int64(time.Hour)))", rnd), true
+	}
+
+	return "", false
+}
+
+// assignRandom returns the statements assigning the random element value to the target, isPtr should be true when the
+// value is already a pointer to the element type. Arrays are filled with count elements.
+func assignRandom(f genField, target, value string, isPtr bool, count string) string {
+	loop := fmt.Sprintf("for i := 0; i < %s; i++ {\n", count)
+	if _, err := strconv.Atoi(count); err != nil {
+		// only evaluate the count once when it is not a constant
+		loop = fmt.Sprintf("for i, n := 0, %s; i < n; i++ {\n", count)
+	}
+
+	switch {
+	case f.array && f.ptr && isPtr:
+		return fmt.Sprintf("%s%s = append(%s, %s)\n}\n", loop, target, target, value)
+	case f.array && f.ptr:
+		return fmt.Sprintf("%sv := %s\n%s = append(%s, &v)\n}\n", loop, value, target, target)
+	case f.array && isPtr:
+		return fmt.Sprintf("%s%s = append(%s, *%s)\n}\n", loop, target, target, value)
+	case f.array:
+		return fmt.Sprintf("%s%s = append(%s, %s)\n}\n", loop, target, target, value)
+	case f.ptr && isPtr:
+		return fmt.Sprintf("%s = %s\n", target, value)
+	case f.ptr:
+		return fmt.Sprintf("{\nv := %s\n%s = &v\n}\n", value, target)
+	case isPtr:
+		return fmt.Sprintf("%s = *%s\n", target, value)
+	default:
+		return fmt.Sprintf("%s = %s\n", target, value)
+	}
+}
+
+// isRecursiveField returns true when the field refers to a struct that contains the struct s, these fields are left as
+// the zero value to avoid infinite recursion
+func isRecursiveField(s genStruct, f genField, structs map[string]genStruct) bool {
+	return f.typ == s.name || reachesStruct(f.typ, s.name, structs, map[string]bool{})
+}
+
+// buildFixtureField returns the statements assigning a random value to the field of the fixture, or an empty
+// string when the field should be left as the zero value
+func buildFixtureField(s genStruct, f genField, structs map[string]genStruct) string {
+	if f.skip {
+		return ""
+	}
+
+	if nested, ok := structs[f.typ]; ok {
+		if isRecursiveField(s, f, structs) {
+			return ""
+		}
+		return assignRandom(f, "result."+f.name, fmt.Sprintf("%s(r.Int63())", formatFixtureName(nested)), true, "2")
+	}
+
+	value, ok := randomValue(f, "r")
+	if !ok {
+		return ""
+	}
+	return assignRandom(f, "result."+f.name, value, false, "2")
+}
+
+func writeFixture(w io.Writer, s genStruct, structs map[string]genStruct) {
+	fixtureName := formatFixtureName(s)
+
+	fmt.Fprintf(w, "// %s generated test fixture for %s, the same seed always returns the same values\n", fixtureName, s.name)
+	fmt.Fprintf(w, "func %s(seed int64, overrides ...func(*%s)) *%s {\n", fixtureName, s.name, s.name)
+
+	var sb strings.Builder
+	for _, f := range s.fields {
+		sb.WriteString(buildFixtureField(s, f, structs))
+	}
+
+	// only seed the random source when at least one field is generated
+	if sb.Len() > 0 {
+		fmt.Fprintln(w, "r := fmrand.New(fmrand.NewSource(seed))")
+	}
+	fmt.Fprintf(w, "result := &%s{}\n", s.name)
+	fmt.Fprint(w, sb.String())
+	fmt.Fprintln(w, "for _, o := range overrides {\no(result)\n}")
+	fmt.Fprintln(w, "return result")
+	fmt.Fprintln(w, "}")
+}
+
+func writeFixtureFile(w io.Writer, pkg string, pkgImports []string, structs []genStruct) {
+	log.Printf("generating test fixture file for package [%s]", pkg)
+
+	byName := structsByName(structs)
+	writeGoFile(w, fixtureFileName, pkg, mergeImports(pkgImports, fixtureImports...), func(buf io.Writer) {
+		for _, s := range structs {
+			writeFixture(buf, s, byName)
+			writeTestFactory(buf, s)
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFormatFixtureName(t *testing.T) {
+	assert.Equal(t, "NewGenStructFixture", formatFixtureName(genStruct{name: "genStruct"}))
+	assert.Equal(t, "NewTestFixture", formatFixtureName(genStruct{name: "Test"}))
+}
+
+func TestReachesStruct(t *testing.T) {
+	structs := structsByName([]genStruct{
+		{name: "A", fields: []genField{{name: "B", typ: "B", ptr: true}}},
+		{name: "B", fields: []genField{{name: "C", typ: "C", array: true}}},
+		{name: "C", fields: []genField{{name: "A", typ: "A", ptr: true}}},
+		{name: "D", fields: []genField{{name: "A", typ: "A", ptr: true, skip: true}, {name: "Name", typ: "string"}}},
+	})
+
+	assert.True(t, reachesStruct("A", "A", structs, map[string]bool{}))
+	assert.True(t, reachesStruct("B", "A", structs, map[string]bool{}))
+	assert.False(t, reachesStruct("A", "D", structs, map[string]bool{}))
+	assert.False(t, reachesStruct("D", "A", structs, map[string]bool{}))
+}
+
+func TestRandomValue(t *testing.T) {
+	t.Run("string hints", func(t *testing.T) {
+		result, ok := randomValue(genField{name: "Email", typ: "string"}, "r")
+		assert.True(t, ok)
+		assert.Equal(t, `fmt.Sprintf("user%d@example.com", r.Intn(10000))`, result)
+
+		result, ok = randomValue(genField{name: "UserID", typ: "string"}, "r")
+		assert.True(t, ok)
+		assert.Equal(t, `fmt.Sprintf("userid-%d", r.Intn(1000000))`, result)
+
+		result, ok = randomValue(genField{name: "Street", typ: "string"}, "r")
+		assert.True(t, ok)
+		assert.Equal(t, `fmt.Sprintf("street-%d", r.Intn(10000))`, result)
+	})
+
+	t.Run("numbers", func(t *testing.T) {
+		result, ok := randomValue(genField{name: "ID", typ: "int64"}, "r")
+		assert.True(t, ok)
+		assert.Equal(t, "int64(1 + r.Intn(100000))", result)
+
+		result, ok = randomValue(genField{name: "Age", typ: "uint"}, "r")
+		assert.True(t, ok)
+		assert.Equal(t, "uint(18 + r.Intn(60))", result)
+
+		result, ok = randomValue(genField{name: "Flags", typ: "uint8"}, "r")
+		assert.True(t, ok)
+		assert.Equal(t, "uint8(r.Intn(100))", result)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, ok := randomValue(genField{name: "BaseURL", typ: "url.URL"}, "r")
+		assert.False(t, ok)
+	})
+}
+
+func TestBuildFixtureField(t *testing.T) {
+	s := genStruct{name: "Sample"}
+	structs := structsByName([]genStruct{
+		s,
+		{name: "Address", fields: []genField{{name: "City", typ: "string"}}},
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		assert.Empty(t, buildFixtureField(s, genField{name: "ID", typ: "int64", skip: true}, structs))
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		assert.Empty(t, buildFixtureField(s, genField{name: "BaseURL", typ: "url.URL"}, structs))
+	})
+
+	t.Run("recursive struct", func(t *testing.T) {
+		assert.Empty(t, buildFixtureField(s, genField{name: "Parent", typ: "Sample", ptr: true}, structs))
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		result := buildFixtureField(s, genField{name: "Active", typ: "bool", ptr: true}, structs)
+		assert.Equal(t, "{\nv := r.Intn(2) == 1\nresult.Active = &v\n}\n", result)
+	})
+
+	t.Run("nested struct", func(t *testing.T) {
+		result := buildFixtureField(s, genField{name: "Home", typ: "Address"}, structs)
+		assert.Equal(t, "result.Home = *NewAddressFixture(r.Int63())\n", result)
+
+		result = buildFixtureField(s, genField{name: "Work", typ: "Address", ptr: true}, structs)
+		assert.Equal(t, "result.Work = NewAddressFixture(r.Int63())\n", result)
+	})
+
+	t.Run("array", func(t *testing.T) {
+		result := buildFixtureField(s, genField{name: "Scores", typ: "float64", array: true, ptr: true}, structs)
+		expected := `for i := 0; i < 2; i++ {
+v := float64(r.Float64() * 100)
+result.Scores = append(result.Scores, &v)
+}
+`
+		assert.Equal(t, expected, result)
+	})
+}
+
+func TestWriteFixture(t *testing.T) {
+	var buf bytes.Buffer
+	writeFixture(&buf, genStruct{name: "Empty"}, nil)
+
+	expected := `// NewEmptyFixture generated test fixture for Empty, the same seed always returns the same values
+func NewEmptyFixture(seed int64, overrides ...func(*Empty)) *Empty {
+result := &Empty{}
+for _, o := range overrides {
+o(result)
+}
+return result
+}
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteFixtureFile(t *testing.T) {
+	buf := bytes.Buffer{}
+	structs := []genStruct{
+		{
+			name: "Sample",
+			fields: []genField{
+				{name: "ID", typ: "int64", skip: true},
+				{name: "Name", typ: "string"},
+				{name: "LastUpdated", typ: "time.Time", optional: true},
+			},
+		},
+	}
+
+	expected := `// Code generated by "fmgen". DO NOT EDIT.
+package testdata
+
+import (
+	"fmt"
+	fmrand "math/rand"
+	"time"
+)
+
+// NewSampleFixture generated test fixture for Sample, the same seed always returns the same values
+func NewSampleFixture(seed int64, overrides ...func(*Sample)) *Sample {
+	r := fmrand.New(fmrand.NewSource(seed))
+	result := &Sample{}
+	result.Name = []string{"Alice", "Bob", "Carol", "Dave", "Erin", "Frank"}[r.Intn(6)]
+	result.LastUpdated = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(r.Int63n(int64(365 * 24 * time.Hour))))
+	for _, o := range overrides {
+		o(result)
+	}
+	return result
+}
+
+// SampleFactory generated test factory for Sample with named traits and sequences
+type SampleFactory struct {
+	seed   int64
+	seq    *int64
+	traits []string
+}
+
+// NewSampleFactory returns a new factory for Sample, the same seed always builds the same values
+func NewSampleFactory(seed int64) *SampleFactory {
+	return &SampleFactory{seed: seed, seq: new(int64)}
+}
+
+// With returns a copy of the factory applying the named traits, sharing the same sequence
+func (f *SampleFactory) With(traits ...string) *SampleFactory {
+	return &SampleFactory{seed: f.seed, seq: f.seq, traits: append(append([]string{}, f.traits...), traits...)}
+}
+
+// Build creates the next Sample in the sequence, traits are applied before any overrides
+func (f *SampleFactory) Build(overrides ...func(*Sample)) *Sample {
+	*f.seq++
+	n := *f.seq
+	result := NewSampleFixture(f.seed + n)
+	for _, t := range f.traits {
+		switch t {
+		default:
+			panic(fmt.Sprintf("unknown SampleFactory trait %q", t))
+		}
+	}
+	for _, o := range overrides {
+		o(result)
+	}
+	return result
+}
+
+// BuildList creates the next n Sample in the sequence
+func (f *SampleFactory) BuildList(n int, overrides ...func(*Sample)) []*Sample {
+	result := make([]*Sample, 0, n)
+	for i := 0; i < n; i++ {
+		result = append(result, f.Build(overrides...))
+	}
+	return result
+}
+`
+
+	writeFixtureFile(&buf, "testdata", []string{`"time"`}, structs)
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// nestedVar returns the variable holding the struct created for a flattened field
+func nestedVar(f genField) string {
+	return f.paramName()
+}
+
+// nestedName returns the factory method creating the struct of a flattened field, which is the name from the -name
+// template until nested fields are assigned
+func (f genField) nestedName() string {
+	if f.nestedFunc != "" {
+		return f.nestedFunc
+	}
+	return formatStructName(f.typ)
+}
+
+// flattenedParams returns the parameters of the factory method of a flattened field prefixed with the field name, only
+// the required parameters are included for fmgen:"flatten" while fmgen:"factory" includes them all
+func flattenedParams(f genField) []genParam {
+	var params []genParam
+	for _, p := range factoryParams(f.nested) {
+		if f.flatten == tagFlatten && p.field.optional {
+			continue
+		}
+
+		// the field is named after its path from the struct
+		field := p.field
+		field.name = f.name + "." + field.name
+		field.param = flattenedName(f, p)
+		params = append(params, genParam{name: field.param, typ: p.typ, field: field, parent: f.name})
+	}
+	return params
+}
+
+// buildNested returns the statements creating the struct of each flattened field with its factory method, optional
+// parameters left out by fmgen:"flatten" are passed in as nil
+func buildNested(fields []genField) string {
+	var sb strings.Builder
+	for _, f := range fields {
+		if f.skip || f.gen != "" || f.flatten == "" {
+			continue
+		}
+
+		var args []string
+		for _, p := range factoryParams(f.nested) {
+			if f.flatten == tagFlatten && p.field.optional {
+				args = append(args, "nil")
+			} else {
+				args = append(args, flattenedName(f, p))
+			}
+		}
+		call := fmt.Sprintf("%s(%s)", f.nestedName(), strings.Join(args, ", "))
+		sb.WriteString(buildCall(f.nested, nestedVar(f), call, "return nil, err\n"))
+	}
+	return sb.String()
+}
+
+// nestedFields returns the fields of the struct with the fields of each flattened struct assigned, path holds the
+// structs being flattened to detect cycles
+func nestedFields(s genStruct, structs map[string]genStruct, path []string) []genField {
+	fields := make([]genField, len(s.fields))
+	for i, f := range s.fields {
+		if f.flatten != "" && !f.skip {
+			nested, ok := structs[f.typ]
+			switch {
+			case !ok || f.array:
+				log.Panicf("%s tag requires a struct within the package, [%s] in struct [%s] is %s", f.flatten, f.name, s.name, valueType(f))
+			case f.optional:
+				log.Panicf("%s tag can't be used on optional field [%s] in struct [%s]", f.flatten, f.name, s.name)
+			case nested.Skip():
+				log.Panicf("missing factory method for [%s] flattened into [%s], %s is skipped", f.typ, s.name, nested.factoryName())
+			}
+			for _, p := range path {
+				if p == f.typ {
+					log.Panicf("flatten cycle detected: %s -> %s", strings.Join(path, " -> "), f.typ)
+				}
+			}
+			f.nested = nestedFields(nested, structs, append(path, f.typ))
+			f.nestedFunc = nested.factoryName()
+		}
+		fields[i] = f
+	}
+	return fields
+}
+
+// assignNested assigns the fields of each flattened struct to the fields flattening it
+func assignNested(structs []genStruct) []genStruct {
+	byName := structsByName(structs)
+	result := make([]genStruct, 0, len(structs))
+	for _, s := range structs {
+		s.fields = nestedFields(s, byName, []string{s.name})
+		result = append(result, s)
+	}
+	return result
+}
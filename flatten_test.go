@@ -0,0 +1,89 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFlattenedParams(t *testing.T) {
+	nested := []genField{
+		{name: "City", typ: "string"},
+		{name: "Notes", typ: "string", optional: true},
+	}
+
+	params := flattenedParams(genField{name: "Home", typ: "Address", flatten: "flatten", nested: nested, param: "home"})
+	assert.Len(t, params, 1)
+	assert.Equal(t, genParam{name: "homeCity", typ: "string", field: genField{name: "Home.City", typ: "string", param: "homeCity"}, parent: "Home"}, params[0])
+
+	params = flattenedParams(genField{name: "Work", typ: "Address", flatten: "factory", nested: nested, param: "work"})
+	assert.Len(t, params, 2)
+	assert.Equal(t, "workCity", params[0].name)
+	assert.Equal(t, "workNotes", params[1].name)
+	assert.Equal(t, "*string", params[1].typ)
+	assert.Equal(t, "Work.Notes", params[1].field.name)
+}
+
+func TestBuildNested(t *testing.T) {
+	nested := []genField{
+		{name: "City", typ: "string"},
+		{name: "Notes", typ: "string", optional: true},
+	}
+	fields := []genField{
+		{name: "Name", typ: "string"},
+		{name: "Home", typ: "Address", flatten: "flatten", nested: nested, param: "home"},
+		{name: "Work", typ: "Address", ptr: true, flatten: "factory", nested: nested, param: "work"},
+	}
+	expected := "home := NewAddress(homeCity, nil)\nwork := NewAddress(workCity, workNotes)\n"
+	assert.Equal(t, expected, buildNested(fields))
+
+	t.Run("errors", func(t *testing.T) {
+		nested := []genField{{name: "Tags", typ: "string", mapped: true}}
+		fields := []genField{{name: "Home", typ: "Address", flatten: "flatten", nested: nested, param: "home"}}
+		expected := "home, err := NewAddress(homeTags)\nif err != nil {\nreturn nil, err\n}\n"
+		assert.Equal(t, expected, buildNested(fields))
+	})
+
+	t.Run("declared name", func(t *testing.T) {
+		fields := []genField{{name: "Home", typ: "Address", flatten: "flatten", nested: nested, nestedFunc: "CreateAddress", param: "home"}}
+		assert.Equal(t, "home := CreateAddress(homeCity, nil)\n", buildNested(fields))
+	})
+}
+
+func TestAssignNested(t *testing.T) {
+	structs := []genStruct{
+		{name: "Geo", fields: []genField{{name: "Lat", typ: "float64"}}},
+		{name: "Address", fields: []genField{{name: "City", typ: "string"}, {name: "Geo", typ: "Geo", flatten: "flatten"}}},
+		{name: "Customer", fields: []genField{{name: "Home", typ: "Address", flatten: "flatten"}}},
+	}
+	results := assignNested(structs)
+	assert.Nil(t, structs[2].fields[0].nested)
+	assert.Equal(t, []genField{{name: "Lat", typ: "float64"}}, results[1].fields[1].nested)
+	assert.Equal(t, []genField{{name: "Lat", typ: "float64"}}, results[2].fields[0].nested[1].nested)
+
+	params := factoryParams(results[2].fields)
+	assert.Len(t, params, 2)
+	assert.Equal(t, "HomeCity", params[0].name)
+	assert.Equal(t, "HomeGeoLat", params[1].name)
+	assert.Equal(t, "Home.Geo.Lat", params[1].field.name)
+	assert.Equal(t, "NewAddress", results[2].fields[0].nestedFunc)
+
+	structs[1].funcName = "CreateAddress"
+	assert.Equal(t, "CreateAddress", assignNested(structs)[2].fields[0].nestedFunc)
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Panics(t, func() {
+			assignNested([]genStruct{{name: "Customer", fields: []genField{{name: "Home", typ: "Address", flatten: "flatten"}}}})
+		})
+		assert.Panics(t, func() {
+			assignNested([]genStruct{
+				{name: "Address", fields: []genField{{name: "City", typ: "string"}}},
+				{name: "Customer", fields: []genField{{name: "Home", typ: "Address", optional: true, flatten: "flatten"}}},
+			})
+		})
+		assert.Panics(t, func() {
+			assignNested([]genStruct{
+				{name: "Node", fields: []genField{{name: "Next", typ: "Node", ptr: true, flatten: "flatten"}}},
+			})
+		})
+	})
+}
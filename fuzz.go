@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+var fuzzImports = []string{`"math"`, `"reflect"`, `"strings"`, `"testing"`, `"time"`}
+
+// types which can be passed directly to a fuzz target
+var fuzzTypes = map[string]bool{
+	"string": true, "bool": true, "byte": true, "rune": true, "float32": true, "float64": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+}
+
+// fuzzArg is a single primitive argument of the fuzz target
+type fuzzArg struct {
+	name string
+	typ  string
+	seed string
+}
+
+func formatFuzzName(s genStruct) string {
+	return "Fuzz" + s.factoryName()
+}
+
+func fuzzSeed(typ string) string {
+	switch typ {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	case "[]byte":
+		return "[]byte{}"
+	default:
+		return fmt.Sprintf("%s(0)", typ)
+	}
+}
+
+func newFuzzArg(name, typ string) fuzzArg {
+	return fuzzArg{name: name, typ: typ, seed: fuzzSeed(typ)}
+}
+
+// decodeFuzzValue returns the fuzz argument and the expression decoding it into a single value of the field, false is
+// returned when the field can't be built from a primitive
+func decodeFuzzValue(f genField) (fuzzArg, string, bool) {
+	switch {
+	case fuzzTypes[f.typ]:
+		return newFuzzArg(f.name, f.typ), f.name, true
+	case f.typ == "time.Time":
+		return newFuzzArg(f.name, "int64"), fmt.Sprintf("time.Unix(0, %s).UTC()", f.name), true
+	case f.typ == "time.Duration":
+		return newFuzzArg(f.name, "int64"), fmt.Sprintf("time.Duration(%s)", f.name), true
+	}
+	return fuzzArg{}, "", false
+}
+
+// buildFuzzParam returns the fuzz arguments and the statements decoding them into the factory parameter named
+// <name>Arg. Parameters which can't be built from primitives are left as the zero value, unless set is true when they
+// are never nil.
+func buildFuzzParam(p genParam, set bool) ([]fuzzArg, string) {
+	// the fuzz arguments are named after the parameter, as flattened fields are named after their path
+	f := p.field
+	f.name = p.name
+	argName := p.name + "Arg"
+
+	if f.array {
+		switch {
+		case f.typ == "string" && !f.ptr:
+			arg := newFuzzArg(f.name, "string")
+			return []fuzzArg{arg}, fmt.Sprintf("var %s %s\nif %s != \"\" {\n%s = strings.Split(%s, \",\")\n}\n", argName, p.typ, f.name, argName, f.name)
+		case (f.typ == "byte" || f.typ == "uint8") && !f.ptr:
+			arg := newFuzzArg(f.name, "[]byte")
+			return []fuzzArg{arg}, fmt.Sprintf("%s := %s(%s)\n", argName, p.typ, f.name)
+		default:
+			return nil, fmt.Sprintf("var %s %s\n", argName, p.typ)
+		}
+	}
+
+	arg, value, ok := decodeFuzzValue(f)
+	if !ok {
+		// checked and set fields must not be nil
+		if v, ok := nilCheckedValue(f); ok && (nilChecked(f) || set) {
+			return nil, fmt.Sprintf("%s := %s\n", argName, v)
+		}
+		if set && f.optional && !f.nilable() {
+			return nil, fmt.Sprintf("%s := new(%s)\n", argName, strings.TrimPrefix(p.typ, "*"))
+		}
+		return nil, fmt.Sprintf("var %s %s\n", argName, p.typ)
+	}
+
+	if f.optional && set {
+		return []fuzzArg{arg}, fmt.Sprintf("var %s %s\n{\nv := %s\n%s = &v\n}\n", argName, p.typ, value, argName)
+	}
+	if f.optional {
+		set := newFuzzArg(f.name+"Set", "bool")
+		return []fuzzArg{arg, set}, fmt.Sprintf("var %s %s\nif %s {\nv := %s\n%s = &v\n}\n", argName, p.typ, set.name, value, argName)
+	}
+	return []fuzzArg{arg}, fmt.Sprintf("%s := %s\n", argName, value)
+}
+
+// buildFuzzEqual returns a condition which is true when got and want are not equal, floats are compared by their bits
+// as NaN is never equal to itself
+func buildFuzzEqual(f genField, got, want string) string {
+	if !f.array && (f.typ == "float32" || f.typ == "float64") {
+		return fmt.Sprintf("math.Float64bits(float64(%s)) != math.Float64bits(float64(%s))", got, want)
+	}
+	return fmt.Sprintf("!reflect.DeepEqual(%s, %s)", got, want)
+}
+
+// buildFuzzCheck returns the statements verifying the field of the result was assigned from the factory parameter
+func buildFuzzCheck(p genParam) string {
+	f := p.field
+	argName := p.name + "Arg"
+	errorf := fmt.Sprintf("t.Errorf(\"%s was not assigned from the factory parameter\")\n", f.name)
+
+	switch {
+	case len(f.normalize) > 0:
+		// normalized fields are not expected to equal the parameter
+		return ""
+	case f.init:
+		// a nil parameter is replaced with an empty value
+		return fmt.Sprintf("if %s == nil {\nif result.%s == nil || len(result.%s) != 0 {\nt.Errorf(\"%s should be empty when nil is passed\")\n}\n} else if %s {\n%s}\n",
+			argName, f.name, f.name, f.name, buildFuzzEqual(f, "result."+f.name, argName), errorf)
+	case f.nilable():
+		// arrays, maps and channels are assigned as is, a nil optional array is skipped leaving the zero value
+		return fmt.Sprintf("if %s {\n%s}\n", buildFuzzEqual(f, "result."+f.name, argName), errorf)
+	case f.optional:
+		got := "result." + f.name
+		if f.ptr {
+			got = "*" + got
+		}
+		return fmt.Sprintf("if %s == nil {\nif !reflect.ValueOf(result.%s).IsZero() {\nt.Errorf(\"%s should be the zero value when nil is passed\")\n}\n} else if %s {\n%s}\n",
+			argName, f.name, f.name, buildFuzzEqual(f, got, "*"+argName), errorf)
+	case f.ptr:
+		return fmt.Sprintf("if %s {\n%s}\n", buildFuzzEqual(f, "*result."+f.name, argName), errorf)
+	default:
+		return fmt.Sprintf("if %s {\n%s}\n", buildFuzzEqual(f, "result."+f.name, argName), errorf)
+	}
+}
+
+func writeFuzz(w io.Writer, s genStruct) {
+	if reason, ok := untestable(s); ok {
+		log.Printf("skipping fuzz test for struct [%s], %s\n", s.name, reason)
+		return
+	}
+	params := factoryParams(s.policyFields())
+	constrained := satisfyingFields(s.fields)
+
+	var args []fuzzArg
+	var setup, checks strings.Builder
+	var callArgs []string
+	for _, p := range params {
+		checks.WriteString(buildFuzzCheck(p))
+		callArgs = append(callArgs, p.name+"Arg")
+		set, isConstrained := constrained[p.field.name]
+		if isConstrained && !set {
+			// constrained parameters which aren't set to satisfy the constraints are always nil
+			setup.WriteString(fmt.Sprintf("var %sArg %s\n", p.name, p.typ))
+			continue
+		}
+
+		pArgs, pSetup := buildFuzzParam(p, set)
+		args = append(args, pArgs...)
+		setup.WriteString(pSetup)
+		if v, ok := nilCheckedValue(p.field); ok && (nilChecked(p.field) || set) && p.field.array {
+			// checked and set arrays must not be nil
+			setup.WriteString(fmt.Sprintf("if %sArg == nil {\n%sArg = %s\n}\n", p.name, p.name, v))
+		}
+	}
+
+	// fields which are skipped by the factory should never be assigned
+	for _, f := range s.fields {
+		if f.skip {
+			checks.WriteString(fmt.Sprintf("if !reflect.ValueOf(result.%s).IsZero() {\nt.Errorf(\"%s should be skipped by the factory\")\n}\n", f.name, f.name))
+		}
+	}
+
+	// a fuzz target requires at least one argument
+	if len(args) == 0 {
+		return
+	}
+
+	var targetParams, seeds []string
+	for _, a := range args {
+		targetParams = append(targetParams, fmt.Sprintf("%s %s", a.name, a.typ))
+		seeds = append(seeds, a.seed)
+	}
+
+	fuzzName := formatFuzzName(s)
+	fmt.Fprintf(w, "// %s generated fuzz test for %s\n", fuzzName, s.factoryName())
+	fmt.Fprintf(w, "func %s(f *testing.F) {\n", fuzzName)
+	fmt.Fprintf(w, "f.Add(%s)\n", strings.Join(seeds, ", "))
+	fmt.Fprintf(w, "f.Fuzz(func(%s) {\n", strings.Join(append([]string{"t *testing.T"}, targetParams...), ", "))
+	fmt.Fprint(w, setup.String())
+	call := fmt.Sprintf("%s(%s)", s.factoryName(), strings.Join(callArgs, ", "))
+	fmt.Fprint(w, buildCall(s.fields, "result", call, fmt.Sprintf("t.Fatalf(\"%s returned an error: %%v\", err)\n", s.factoryName())))
+	fmt.Fprintln(w, "if result == nil {")
+	fmt.Fprintf(w, "t.Fatal(\"%s returned nil\")\n", s.factoryName())
+	fmt.Fprintln(w, "}")
+	fmt.Fprint(w, checks.String())
+	fmt.Fprintln(w, "})")
+	fmt.Fprintln(w, "}")
+}
+
+func writeFuzzFile(w io.Writer, pkg string, pkgImports []string, structs []genStruct) {
+	log.Printf("generating fuzz test file for package [%s]", pkg)
+
+	writeGoFile(w, fuzzFileName, pkg, mergeImports(pkgImports, fuzzImports...), func(buf io.Writer) {
+		for _, s := range structs {
+			writeFuzz(buf, s)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFormatFuzzName(t *testing.T) {
+	assert.Equal(t, "FuzzNewGenStruct", formatFuzzName(genStruct{name: "genStruct"}))
+	assert.Equal(t, "FuzzNewTest", formatFuzzName(genStruct{name: "Test"}))
+}
+
+func TestBuildFuzzParam(t *testing.T) {
+	t.Run("primitive", func(t *testing.T) {
+		args, setup := buildFuzzParam(genParam{name: "Name", typ: "string", field: genField{name: "Name", typ: "string"}}, false)
+		assert.Equal(t, []fuzzArg{{name: "Name", typ: "string", seed: `""`}}, args)
+		assert.Equal(t, "NameArg := Name\n", setup)
+	})
+
+	t.Run("optional time", func(t *testing.T) {
+		f := genField{name: "Created", typ: "time.Time", optional: true}
+		args, setup := buildFuzzParam(genParam{name: "Created", typ: "*time.Time", field: f}, false)
+		expected := []fuzzArg{
+			{name: "Created", typ: "int64", seed: "int64(0)"},
+			{name: "CreatedSet", typ: "bool", seed: "false"},
+		}
+		assert.Equal(t, expected, args)
+		assert.Equal(t, "var CreatedArg *time.Time\nif CreatedSet {\nv := time.Unix(0, Created).UTC()\nCreatedArg = &v\n}\n", setup)
+	})
+
+	t.Run("set optional time", func(t *testing.T) {
+		f := genField{name: "Created", typ: "time.Time", optional: true, anyOf: "dates"}
+		args, setup := buildFuzzParam(genParam{name: "Created", typ: "*time.Time", field: f}, true)
+		assert.Equal(t, []fuzzArg{{name: "Created", typ: "int64", seed: "int64(0)"}}, args)
+		assert.Equal(t, "var CreatedArg *time.Time\n{\nv := time.Unix(0, Created).UTC()\nCreatedArg = &v\n}\n", setup)
+	})
+
+	t.Run("set unsupported", func(t *testing.T) {
+		f := genField{name: "BaseURL", typ: "url.URL", optional: true, oneOf: "source"}
+		args, setup := buildFuzzParam(genParam{name: "BaseURL", typ: "*url.URL", field: f}, true)
+		assert.Empty(t, args)
+		assert.Equal(t, "BaseURLArg := new(url.URL)\n", setup)
+
+		f = genField{name: "Attrs", typ: "map[string]int", mapped: true, optional: true, oneOf: "source"}
+		args, setup = buildFuzzParam(genParam{name: "Attrs", typ: "map[string]int", field: f}, true)
+		assert.Empty(t, args)
+		assert.Equal(t, "AttrsArg := make(map[string]int)\n", setup)
+	})
+
+	t.Run("string array", func(t *testing.T) {
+		f := genField{name: "Tags", typ: "string", array: true}
+		args, setup := buildFuzzParam(genParam{name: "Tags", typ: "[]string", field: f}, false)
+		assert.Equal(t, []fuzzArg{{name: "Tags", typ: "string", seed: `""`}}, args)
+		assert.Equal(t, "var TagsArg []string\nif Tags != \"\" {\nTagsArg = strings.Split(Tags, \",\")\n}\n", setup)
+	})
+
+	t.Run("byte array", func(t *testing.T) {
+		f := genField{name: "Data", typ: "byte", array: true}
+		args, setup := buildFuzzParam(genParam{name: "Data", typ: "[]byte", field: f}, false)
+		assert.Equal(t, []fuzzArg{{name: "Data", typ: "[]byte", seed: "[]byte{}"}}, args)
+		assert.Equal(t, "DataArg := []byte(Data)\n", setup)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		f := genField{name: "BaseURL", typ: "url.URL"}
+		args, setup := buildFuzzParam(genParam{name: "BaseURL", typ: "url.URL", field: f}, false)
+		assert.Empty(t, args)
+		assert.Equal(t, "var BaseURLArg url.URL\n", setup)
+	})
+}
+
+func TestBuildFuzzCheck(t *testing.T) {
+	t.Run("float", func(t *testing.T) {
+		f := genField{name: "Score", typ: "float64", ptr: true}
+		expected := `if math.Float64bits(float64(*result.Score)) != math.Float64bits(float64(ScoreArg)) {
+t.Errorf("Score was not assigned from the factory parameter")
+}
+`
+		assert.Equal(t, expected, buildFuzzCheck(genParam{name: "Score", typ: "float64", field: f}))
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		f := genField{name: "Age", typ: "int64", optional: true}
+		expected := `if AgeArg == nil {
+if !reflect.ValueOf(result.Age).IsZero() {
+t.Errorf("Age should be the zero value when nil is passed")
+}
+} else if !reflect.DeepEqual(result.Age, *AgeArg) {
+t.Errorf("Age was not assigned from the factory parameter")
+}
+`
+		assert.Equal(t, expected, buildFuzzCheck(genParam{name: "Age", typ: "*int64", field: f}))
+	})
+
+	t.Run("initialized", func(t *testing.T) {
+		f := genField{name: "Attrs", typ: "map[string]int", mapped: true, optional: true, init: true}
+		expected := `if AttrsArg == nil {
+if result.Attrs == nil || len(result.Attrs) != 0 {
+t.Errorf("Attrs should be empty when nil is passed")
+}
+} else if !reflect.DeepEqual(result.Attrs, AttrsArg) {
+t.Errorf("Attrs was not assigned from the factory parameter")
+}
+`
+		assert.Equal(t, expected, buildFuzzCheck(genParam{name: "Attrs", typ: "map[string]int", field: f}))
+	})
+
+	t.Run("normalized", func(t *testing.T) {
+		f := genField{name: "Email", typ: "string", normalize: []string{"trim"}}
+		assert.Empty(t, buildFuzzCheck(genParam{name: "Email", typ: "string", field: f}))
+	})
+}
+
+func TestWriteFuzzFile(t *testing.T) {
+	buf := bytes.Buffer{}
+	structs := []genStruct{
+		{
+			name: "Sample",
+			fields: []genField{
+				{name: "ID", typ: "int64", skip: true},
+				{name: "Name", typ: "string"},
+				{name: "Age", typ: "int64", optional: true},
+			},
+		},
+		{
+			name: "Unsupported",
+			fields: []genField{
+				{name: "BaseURL", typ: "url.URL"},
+			},
+		},
+	}
+
+	expected := `// Code generated by "fmgen". DO NOT EDIT.
+package testdata
+
+import (
+	"reflect"
+	"testing"
+)
+
+// FuzzNewSample generated fuzz test for NewSample
+func FuzzNewSample(f *testing.F) {
+	f.Add("", int64(0), false)
+	f.Fuzz(func(t *testing.T, Name string, Age int64, AgeSet bool) {
+		NameArg := Name
+		var AgeArg *int64
+		if AgeSet {
+			v := Age
+			AgeArg = &v
+		}
+		result := NewSample(NameArg, AgeArg)
+		if result == nil {
+			t.Fatal("NewSample returned nil")
+		}
+		if !reflect.DeepEqual(result.Name, NameArg) {
+			t.Errorf("Name was not assigned from the factory parameter")
+		}
+		if AgeArg == nil {
+			if !reflect.ValueOf(result.Age).IsZero() {
+				t.Errorf("Age should be the zero value when nil is passed")
+			}
+		} else if !reflect.DeepEqual(result.Age, *AgeArg) {
+			t.Errorf("Age was not assigned from the factory parameter")
+		}
+		if !reflect.ValueOf(result.ID).IsZero() {
+			t.Errorf("ID should be skipped by the factory")
+		}
+	})
+}
+`
+
+	writeFuzzFile(&buf, "testdata", []string{`"net/url"`}, structs)
+	assert.Equal(t, expected, buf.String())
+}
@@ -12,6 +12,9 @@ import (
 	"unicode"
 )
 
+// imports used by the normalized and computed fields, pools, sealed interfaces and defined types within the generated file
+var packageImports = []string{`"fmt"`, `"sort"`, `"strconv"`, `"strings"`, `"sync"`, `"time"`}
+
 func writeImports(w io.Writer, imports []string) {
 	if imports == nil || len(imports) == 0 {
 		return
@@ -26,6 +29,13 @@ func writeImports(w io.Writer, imports []string) {
 	fmt.Fprintln(w, ")")
 }
 
+// mergeImports returns a new list of imports containing both the package imports and any extra imports
+func mergeImports(pkgImports []string, extra ...string) []string {
+	result := make([]string, 0, len(pkgImports)+len(extra))
+	result = append(result, pkgImports...)
+	return append(result, extra...)
+}
+
 func bool2int(b bool) int {
 	if b {
 		return 1
@@ -33,96 +43,288 @@ func bool2int(b bool) int {
 	return 0
 }
 
-func buildInputParams(fields []genField) string {
-	var fieldList []string
+// genParam is a single input parameter of a generated factory method
+type genParam struct {
+	name  string
+	typ   string
+	field genField
+	// the flattened field the parameter is passed to the factory method of
+	parent string
+}
 
-	// sort fields, required first
-	sort.SliceStable(fields, func(i, j int) bool {
-		return bool2int(fields[i].optional) < bool2int(fields[j].optional)
+// factoryParams returns the input parameters of the factory method, required fields first
+func factoryParams(fields []genField) []genParam {
+	var params []genParam
+
+	// sort a copy of the fields, leaving the order of the struct literal unchanged
+	sorted := make([]genField, len(fields))
+	copy(sorted, fields)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return paramLess(sorted[i], sorted[j])
 	})
 
-	for _, f := range fields {
-		if f.skip {
+	for _, f := range sorted {
+		// computed fields are not passed in
+		if f.skip || f.gen != "" {
+			continue
+		}
+
+		if f.flatten != "" {
+			params = append(params, flattenedParams(f)...)
 			continue
 		}
 
 		var optionalStr string
-		if f.optional && !f.array {
+		if f.optional && !f.nilable() {
 			optionalStr = "*"
 		}
 
-		if f.array {
+		var typ string
+		if f.conv != "" {
+			// converted params are passed in with their own type
+			typ = fmt.Sprintf("%s%s", optionalStr, f.paramType)
+		} else if f.array {
 			if f.ptr {
-				fieldList = append(fieldList, fmt.Sprintf("%s %s[]*%s", f.name, optionalStr, f.typ))
+				typ = fmt.Sprintf("%s[]*%s", optionalStr, f.typ)
 			} else {
-				fieldList = append(fieldList, fmt.Sprintf("%s %s[]%s", f.name, optionalStr, f.typ))
+				typ = fmt.Sprintf("%s[]%s", optionalStr, f.typ)
 			}
 		} else {
-			fieldList = append(fieldList, fmt.Sprintf("%s %s%s", f.name, optionalStr, f.typ))
+			typ = fmt.Sprintf("%s%s", optionalStr, f.typ)
 		}
+
+		params = append(params, genParam{name: f.paramName(), typ: typ, field: f})
+	}
+
+	return params
+}
+
+// ownParams returns the input parameters of the factory method assigned directly to the fields of the struct, leaving
+// out the parameters passed to the factory methods of flattened fields
+func ownParams(fields []genField) []genParam {
+	var params []genParam
+	for _, p := range factoryParams(fields) {
+		if p.parent == "" {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+func buildInputParams(fields []genField) string {
+	var fieldList []string
+
+	for _, p := range factoryParams(fields) {
+		fieldList = append(fieldList, fmt.Sprintf("%s %s", p.name, p.typ))
 	}
 
 	return strings.Join(fieldList, ",")
 }
 
-func buildBody(name string, fields []genField) string {
+// requiredValue returns the value assigned to a required field from its input param, if the field is not an array and
+// a pointer then the address of the input param is used
+func requiredValue(f genField) string {
+	if f.flatten != "" {
+		// flattened fields are created before the struct
+		if f.ptr {
+			return nestedVar(f)
+		}
+		return "*" + nestedVar(f)
+	}
+	if f.ptr && !f.array {
+		return "&" + f.paramName()
+	}
+	return f.paramName()
+}
+
+// buildAssignment returns the statement assigning the value to the field of the result, if the field is not an array
+// and a pointer then the address of the value is used
+func buildAssignment(f genField, value string) string {
+	if f.ptr && !f.array {
+		return fmt.Sprintf("{\nv := %s\nresult.%s = &v\n}\n", value, f.name)
+	}
+	return fmt.Sprintf("result.%s = %s\n", f.name, value)
+}
+
+// buildComputedAssignments returns the statements assigning each computed field of the result from its function
+func buildComputedAssignments(fields []genField) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("result := &%s {\n", name))
+	for _, f := range fields {
+		if !f.skip && f.gen != "" {
+			sb.WriteString(buildAssignment(f, f.gen+"()"))
+		}
+	}
+	return sb.String()
+}
 
-	// process required fields
+// buildOptionalAssignments returns the statements assigning each optional field of the result when its input param is
+// not nil, arrays are copied into the storage kept by the pool when one is used
+func buildOptionalAssignments(pool string, fields []genField) string {
+	var sb strings.Builder
 	for _, f := range fields {
-		if f.skip || f.optional {
+		if f.skip || !f.optional || f.gen != "" {
 			continue
 		}
 
-		// if field is required, not an array, and a pointer, then set to the address of the input param
-		if f.ptr && !f.array {
-			sb.WriteString(fmt.Sprintf("%s: &%s,\n", f.name, f.name))
-		} else {
-			sb.WriteString(fmt.Sprintf("%s: %s,\n", f.name, f.name))
+		// pointers, arrays and maps are passed in as is, while other optional fields are passed in as a pointer
+		switch {
+		case pool != "" && f.array:
+			sb.WriteString(fmt.Sprintf("if %s != nil {\nresult.%s = %s\n}\n", f.paramName(), f.name, pooledArray(f, f.paramName())))
+		case f.ptr || f.nilable():
+			sb.WriteString(fmt.Sprintf("if %s != nil {\nresult.%s = %s\n}\n", f.paramName(), f.name, f.paramName()))
+		default:
+			sb.WriteString(fmt.Sprintf("if %s != nil {\nresult.%s = *%s\n}\n", f.paramName(), f.name, f.paramName()))
 		}
 	}
+	return sb.String()
+}
 
-	sb.WriteString("}\n")
+// pooledArray returns the value appending the array to the storage of the field kept by the pool, so the capacity of a
+// released result is reused and arrays passed in aren't retained
+func pooledArray(f genField, value string) string {
+	return fmt.Sprintf("append(result.%s[:0], %s...)", f.name, value)
+}
+
+// buildAllocation returns the statements allocating the result with its required fields assigned, the result is taken
+// from the pool instead when one is passed in
+func buildAllocation(name, pool string, fields []genField) string {
+	var sb strings.Builder
+	if pool != "" {
+		sb.WriteString(fmt.Sprintf("result := %s.Get().(*%s)\n", pool, name))
+	} else {
+		sb.WriteString(fmt.Sprintf("result := &%s {\n", name))
+	}
 
-	// process optional fields
 	for _, f := range fields {
-		if f.skip || !f.optional {
+		if f.skip || f.optional || f.gen != "" {
 			continue
 		}
-
-		if f.ptr {
-			sb.WriteString(fmt.Sprintf("if %s != nil {\nresult.%s = %s\n}\n", f.name, f.name, f.name))
-		} else {
-			sb.WriteString(fmt.Sprintf("if %s != nil {\nresult.%s = *%s\n}\n", f.name, f.name, f.name))
+		switch {
+		case pool != "" && f.array:
+			sb.WriteString(fmt.Sprintf("result.%s = %s\n", f.name, pooledArray(f, requiredValue(f))))
+		case pool != "":
+			sb.WriteString(fmt.Sprintf("result.%s = %s\n", f.name, requiredValue(f)))
+		default:
+			sb.WriteString(fmt.Sprintf("%s: %s,\n", f.name, requiredValue(f)))
 		}
 	}
 
-	sb.WriteString("return result\n")
+	if pool == "" {
+		sb.WriteString("}\n")
+	}
+	return sb.String()
+}
+
+// buildBody returns the statements of a factory method creating the struct from its input params, the result is taken
+// from the pool when one is passed in
+func buildBody(funcName, name, pool string, fields []genField) string {
+	var sb strings.Builder
+
+	// reject nil and constrained input params, then convert, copy, normalize and initialize them before they are
+	// assigned
+	sb.WriteString(buildNilChecks(funcName, name, fields))
+	sb.WriteString(buildConstraints(funcName, name, fields))
+	conversions, assigned := buildConversions(funcName, name, fields)
+	sb.WriteString(conversions)
+	sb.WriteString(buildCopies(name, assigned))
+	sb.WriteString(buildNormalize(name, assigned))
+	sb.WriteString(buildInits(name, assigned))
+	sb.WriteString(buildNested(assigned))
+
+	// process required fields
+	sb.WriteString(buildAllocation(name, pool, assigned))
+
+	// process computed fields
+	sb.WriteString(buildComputedAssignments(assigned))
+
+	// process optional fields
+	sb.WriteString(buildOptionalAssignments(pool, assigned))
+
+	sb.WriteString(buildReturn(fields, "result"))
 
 	return sb.String()
 }
 
-func formatStructName(in string) string {
-	return "New" + string(unicode.ToUpper(rune(in[0]))) + in[1:]
+func capitalize(in string) string {
+	return string(unicode.ToUpper(rune(in[0]))) + in[1:]
 }
 
-func writeStruct(w io.Writer, s genStruct) {
-	fmFuncName := formatStructName(s.name)
-	comment := fmt.Sprintf("// %s generated factory method for %s", fmFuncName, s.name)
+// formatStructName returns the factory method name from the -name template, structs may declare their own name which is
+// returned by factoryName
+func formatStructName(in string) string {
+	return executeName(*flagName, in)
+}
 
-	// struct comment
+// writeFactory writes a factory method for the struct taking the fields passed in, documented from the struct and its
+// fields. The result is taken from the pool when one is passed in
+func writeFactory(w io.Writer, funcName, comment string, s genStruct, fields []genField, pool string) {
 	fmt.Fprintln(w, comment)
+	fmt.Fprint(w, buildDoc(s, fields))
 
 	// struct method signature
-	fmt.Fprintf(w, "func %s(%s) *%s{\n", fmFuncName, buildInputParams(s.fields), s.name)
+	fmt.Fprintf(w, "func %s(%s) %s{\n", funcName, buildInputParams(fields), buildReturnType(s.name, fields))
 
 	// build struct body
-	fmt.Fprintf(w, buildBody(s.name, s.fields))
+	fmt.Fprint(w, buildBody(funcName, s.name, pool, fields))
 	fmt.Fprintln(w, "}")
 }
 
-func writePackageFile(w io.Writer, pkg string, pkgImports []string, structs []genStruct) {
+func writeStruct(w io.Writer, s genStruct) {
+	fmFuncName := s.factoryName()
+	writeFactory(w, fmFuncName, fmt.Sprintf("// %s generated factory method for %s", fmFuncName, s.name), s, s.policyFields(), "")
+
+	// named constructors with their own required fields
+	for _, c := range s.ctors() {
+		comment := fmt.Sprintf("// %s generated %s factory method for %s", c.funcName, c.profile, s.name)
+		writeFactory(w, c.funcName, comment, s, s.ctorFields(c.profile), "")
+	}
+}
+
+func writePackageFile(w io.Writer, pkg string, pkgImports []string, structs []genStruct, interfaces []genInterface, genTypes []genType) {
+	log.Printf("generating factory method file for package [%s]", pkg)
+
+	writeGoFile(w, generatedFileName, pkg, mergeImports(pkgImports, packageImports...), func(buf io.Writer) {
+		// write factory methods for each struct
+		for _, s := range structs {
+			if !s.Skip() {
+				writeStruct(buf, s)
+			}
+			if !s.Skip() && s.pooled() {
+				writePool(buf, s)
+			}
+		}
+
+		// write constructors and visitors for each sealed interface
+		byName := interfacesByName(interfaces)
+		for _, i := range interfaces {
+			if i.sealed() && !i.Skip() {
+				writeSealed(buf, i, byName, structs)
+			}
+		}
+
+		// write injectors for each root struct
+		for _, s := range structs {
+			if !s.Skip() && s.injected() {
+				writeInjector(buf, s, structs)
+			}
+		}
+
+		// write constructors and validation for each defined type
+		for _, t := range genTypes {
+			switch {
+			case !t.generated() || t.Skip():
+			case len(t.declared) > 0:
+				log.Printf("skipping defined type [%s], %s already declared in the package\n", t.name, strings.Join(t.declared, ", "))
+			default:
+				writeDefinedType(buf, t)
+			}
+		}
+	})
+}
+
+// writeGoFile writes the generated header, package and imports followed by the body, then formats the
+// results and removes any unused imports
+func writeGoFile(w io.Writer, filename, pkg string, pkgImports []string, body func(io.Writer)) {
 	var buf bytes.Buffer
 	var err error
 
@@ -135,15 +337,10 @@ func writePackageFile(w io.Writer, pkg string, pkgImports []string, structs []ge
 	// write the imports
 	writeImports(&buf, pkgImports)
 
-	// write factory methods for each struct
-	for _, s := range structs {
-		if !s.Skip() {
-			writeStruct(&buf, s)
-		}
-	}
+	// write the contents of the file
+	body(&buf)
 
-	output := fmt.Sprintf("%s/%s", pkg, generatedFileName)
-	log.Printf("generating factory method file for package [%s]", pkg)
+	output := fmt.Sprintf("%s/%s", pkg, filename)
 
 	opts := &imports.Options{
 		Fragment:   false,
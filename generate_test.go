@@ -11,6 +11,18 @@ func TestFormatStructName(t *testing.T) {
 	assert.Equal(t, "NewTest", formatStructName("Test"))
 }
 
+func TestMergeImports(t *testing.T) {
+	pkgImports := make([]string, 1, 2)
+	pkgImports[0] = `"time"`
+
+	result := mergeImports(pkgImports, `"fmt"`)
+	assert.Equal(t, []string{`"time"`, `"fmt"`}, result)
+
+	// the package imports should not be modified
+	result[0] = `"net/url"`
+	assert.Equal(t, []string{`"time"`}, pkgImports)
+}
+
 func TestWritePackageFile(t *testing.T) {
 	buf := bytes.Buffer{}
 	structs := []genStruct{
@@ -53,6 +65,13 @@ import (
 )
 
 // NewSample generated factory method for Sample
+//
+// Sample simple struct.
+//
+// Parameters:
+//   - Name
+//   - Age: optional, nil leaves Age unset
+//   - LastUpdated: optional, nil leaves LastUpdated unset
 func NewSample(Name string, Age *int64, LastUpdated *time.Time) *Sample {
 	result := &Sample{
 		Name: Name,
@@ -67,7 +86,7 @@ func NewSample(Name string, Age *int64, LastUpdated *time.Time) *Sample {
 }
 `
 
-	writePackageFile(&buf, "testdata", imports, structs)
+	writePackageFile(&buf, "testdata", imports, structs, nil, nil)
 	assert.Equal(t, expected, buf.String())
 }
 
@@ -144,6 +163,96 @@ func TestBuildInputParams(t *testing.T) {
 }
 
 func TestBuildBody(t *testing.T) {
+	t.Run("computed", func(t *testing.T) {
+		fields := []genField{
+			{name: "ID", typ: "string", gen: "newRequestID"},
+			{name: "Name", typ: "string"},
+			{name: "CreatedAt", typ: "time.Time", gen: "time.Now"},
+			{name: "UpdatedAt", typ: "time.Time", ptr: true, optional: true, gen: "time.Now"},
+			{name: "Skipped", typ: "string", skip: true, gen: "newSkipped"},
+		}
+		result := buildBody("NewSimple", "Simple", "", fields)
+		expected := `result := &Simple {
+Name: Name,
+}
+result.ID = newRequestID()
+result.CreatedAt = time.Now()
+{
+v := time.Now()
+result.UpdatedAt = &v
+}
+return result
+`
+		assert.Equal(t, expected, result)
+		assert.Equal(t, "Name string", buildInputParams(fields))
+	})
+
+	t.Run("pooled", func(t *testing.T) {
+		fields := []genField{
+			{name: "ID", typ: "string", gen: "newRequestID"},
+			{name: "Name", typ: "string"},
+			{name: "Age", typ: "int", optional: true},
+		}
+		result := buildBody("AcquireSimple", "Simple", "simplePool", fields)
+		expected := `result := simplePool.Get().(*Simple)
+result.Name = Name
+result.ID = newRequestID()
+if Age != nil {
+result.Age = *Age
+}
+return result
+`
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("normalized", func(t *testing.T) {
+		fields := []genField{
+			{name: "Email", typ: "string", normalize: []string{"trim", "lower"}},
+			{name: "Nickname", typ: "string", optional: true, normalize: []string{"trim"}},
+		}
+		result := buildBody("NewSimple", "Simple", "", fields)
+		expected := `Email = strings.ToLower(strings.TrimSpace(Email))
+if Nickname != nil {
+v := *Nickname
+v = strings.TrimSpace(v)
+Nickname = &v
+}
+result := &Simple {
+Email: Email,
+}
+if Nickname != nil {
+result.Nickname = *Nickname
+}
+return result
+`
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("maps", func(t *testing.T) {
+		fields := []genField{
+			{name: "Labels", typ: "map[string]string", mapped: true, copy: true, allowNil: true},
+			{name: "Attrs", typ: "map[string]int", mapped: true, optional: true},
+		}
+		result := buildBody("NewSimple", "Simple", "", fields)
+		expected := `if Labels != nil {
+c := make(map[string]string, len(Labels))
+for k, v := range Labels {
+c[k] = v
+}
+Labels = c
+}
+result := &Simple {
+Labels: Labels,
+}
+if Attrs != nil {
+result.Attrs = Attrs
+}
+return result
+`
+		assert.Equal(t, expected, result)
+		assert.Equal(t, "Labels map[string]string,Attrs map[string]int", buildInputParams(fields))
+	})
+
 	t.Run("all required", func(t *testing.T) {
 		fields := []genField{
 			{
@@ -168,7 +277,7 @@ func TestBuildBody(t *testing.T) {
 				ptr:      false,
 			},
 		}
-		result := buildBody("Simple", fields)
+		result := buildBody("NewSimple", "Simple", "", fields)
 		expected := `result := &Simple {
 A: A,
 B: &B,
@@ -197,7 +306,7 @@ return result
 				ptr:      true,
 			},
 		}
-		result := buildBody("Simple", fields)
+		result := buildBody("NewSimple", "Simple", "", fields)
 		expected := `result := &Simple {
 A: A,
 B: B,
@@ -207,6 +316,36 @@ return result
 		assert.Equal(t, expected, result)
 	})
 
+	t.Run("optional arrays", func(t *testing.T) {
+		fields := []genField{
+			{
+				name:     "A",
+				typ:      "string",
+				array:    true,
+				optional: true,
+			},
+			{
+				name:     "B",
+				typ:      "int64",
+				array:    true,
+				optional: true,
+				ptr:      true,
+			},
+		}
+		result := buildBody("NewSimple", "Simple", "", fields)
+		expected := `result := &Simple {
+}
+if A != nil {
+result.A = A
+}
+if B != nil {
+result.B = B
+}
+return result
+`
+		assert.Equal(t, expected, result)
+	})
+
 	t.Run("include optional", func(t *testing.T) {
 		fields := []genField{
 			{
@@ -238,7 +377,7 @@ return result
 				ptr:      false,
 			},
 		}
-		result := buildBody("Simple", fields)
+		result := buildBody("NewSimple", "Simple", "", fields)
 		expected := `result := &Simple {
 A: A,
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// initValue returns the expression creating an empty value of the array, map or channel field
+func initValue(name string, f genField) string {
+	if f.buffer != "" {
+		if n, err := strconv.Atoi(f.buffer); !f.channel || err != nil || n < 0 {
+			log.Panicf("invalid init buffer size [%s] for field [%s] in struct [%s], only channels have a buffer", f.buffer, f.name, name)
+		}
+	}
+
+	switch {
+	case f.array:
+		return valueType(f) + "{}"
+	case f.channel && f.buffer != "":
+		return fmt.Sprintf("make(%s, %s)", f.typ, f.buffer)
+	default:
+		return fmt.Sprintf("make(%s)", f.typ)
+	}
+}
+
+// buildInits returns the statements replacing each nil input param of a field tagged with fmgen:"init" with an empty
+// value, so the result never has a nil array, map or channel
+func buildInits(name string, fields []genField) string {
+	var sb strings.Builder
+	for _, p := range ownParams(fields) {
+		f := p.field
+		if !f.init {
+			continue
+		}
+		if !f.array && !f.mapped && !f.channel {
+			log.Panicf("init tag requires an array, map or channel field, [%s] in struct [%s] is %s", f.name, name, valueType(f))
+		}
+		sb.WriteString(fmt.Sprintf("if %s == nil {\n%s = %s\n}\n", p.name, p.name, initValue(name, f)))
+	}
+	return sb.String()
+}
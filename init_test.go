@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestInitValue(t *testing.T) {
+	assert.Equal(t, "[]string{}", initValue("Sample", genField{name: "Tags", typ: "string", array: true}))
+	assert.Equal(t, "[]*Sample{}", initValue("Sample", genField{name: "Children", typ: "Sample", array: true, ptr: true}))
+	assert.Equal(t, "make(map[string]int)", initValue("Sample", genField{name: "Attrs", typ: "map[string]int", mapped: true}))
+	assert.Equal(t, "make(chan int)", initValue("Sample", genField{name: "Events", typ: "chan int", channel: true}))
+	assert.Equal(t, "make(chan int, 16)", initValue("Sample", genField{name: "Events", typ: "chan int", channel: true, buffer: "16"}))
+
+	assert.Panics(t, func() {
+		initValue("Sample", genField{name: "Events", typ: "chan int", channel: true, buffer: "many"})
+	})
+	assert.Panics(t, func() {
+		initValue("Sample", genField{name: "Attrs", typ: "map[string]int", mapped: true, buffer: "16"})
+	})
+}
+
+func TestBuildInits(t *testing.T) {
+	t.Run("initialized", func(t *testing.T) {
+		fields := []genField{
+			{name: "Name", typ: "string"},
+			{name: "Attrs", typ: "map[string]int", mapped: true, optional: true, init: true},
+			{name: "Tags", typ: "string", array: true, optional: true},
+		}
+		expected := "if Attrs == nil {\nAttrs = make(map[string]int)\n}\n"
+		assert.Equal(t, expected, buildInits("Sample", fields))
+	})
+
+	t.Run("not an array, map or channel", func(t *testing.T) {
+		assert.Panics(t, func() {
+			buildInits("Sample", []genField{{name: "Name", typ: "string", init: true}})
+		})
+	})
+}
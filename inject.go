@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+func formatInjectorName(in string) string {
+	return "Initialize" + capitalize(in)
+}
+
+// injector builds the statements creating a root struct along with all of its dependencies, each struct is only
+// created once and shared by everything depending on it
+type injector struct {
+	structs map[string]genStruct
+	vars    map[string]string
+	names   map[string]bool
+	params  []string
+	body    strings.Builder
+	// whether any factory method returns an error
+	errs bool
+}
+
+func newInjector(structs []genStruct) *injector {
+	return &injector{
+		structs: structsByName(structs),
+		vars:    make(map[string]string),
+		names:   make(map[string]bool),
+	}
+}
+
+// uniqueName returns the name, adding a number when it was already declared
+func (i *injector) uniqueName(name string) string {
+	result := name
+	for n := 2; i.names[result]; n++ {
+		result = fmt.Sprintf("%s%d", name, n)
+	}
+	i.names[result] = true
+	return result
+}
+
+// build writes the statements creating the struct after its dependencies, returning the variable holding the result
+func (i *injector) build(name string, path []string) string {
+	if v, ok := i.vars[name]; ok {
+		return v
+	}
+	for _, p := range path {
+		if p == name {
+			log.Panicf("dependency cycle detected: %s -> %s", strings.Join(path, " -> "), name)
+		}
+	}
+	path = append(path, name)
+
+	s := i.structs[name]
+	var args, shared []string
+	for _, p := range factoryParams(s.fields) {
+		f := p.field
+		dep, ok := i.structs[f.typ]
+		switch {
+		case ok && !f.array:
+			if dep.Skip() {
+				log.Panicf("missing provider for [%s] required by [%s], %s is skipped", f.typ, s.name, dep.factoryName())
+			}
+
+			v := i.build(f.typ, path)
+			switch {
+			case strings.HasPrefix(p.typ, "*"):
+				args = append(args, v)
+			case f.ptr:
+				// factory methods take required pointer fields by value, so the field is assigned after the call to
+				// share the dependency rather than a copy of it
+				args = append(args, f.typ+"{}")
+				shared = append(shared, fmt.Sprintf("%s = %s\n", f.name, v))
+			default:
+				args = append(args, "*"+v)
+			}
+		case f.optional:
+			args = append(args, "nil")
+		default:
+			// anything which isn't a struct within the package is passed in to the injector
+			param := s.name
+			for _, part := range strings.Split(f.name, ".") {
+				param += capitalize(part)
+			}
+			param = i.uniqueName(lowerCamel(param))
+			i.params = append(i.params, fmt.Sprintf("%s %s", param, p.typ))
+			args = append(args, param)
+		}
+	}
+
+	v := i.uniqueName(lowerFirst(s.name))
+	i.vars[name] = v
+	i.errs = i.errs || returnsError(s.fields)
+	call := fmt.Sprintf("%s(%s)", s.factoryName(), strings.Join(args, ", "))
+	i.body.WriteString(buildCall(s.fields, v, call, "return nil, err\n"))
+	for _, assignment := range shared {
+		i.body.WriteString(v + "." + assignment)
+	}
+	return v
+}
+
+func writeInjector(w io.Writer, root genStruct, structs []genStruct) {
+	i := newInjector(structs)
+	result := i.build(root.name, nil)
+
+	injectorName := formatInjectorName(root.name)
+	fmt.Fprintf(w, "// %s generated injector creating %s along with its dependencies\n", injectorName, root.name)
+	if i.errs {
+		fmt.Fprintf(w, "func %s(%s) (*%s, error) {\n", injectorName, strings.Join(i.params, ", "), root.name)
+		fmt.Fprint(w, i.body.String())
+		fmt.Fprintf(w, "return %s, nil\n", result)
+	} else {
+		fmt.Fprintf(w, "func %s(%s) *%s {\n", injectorName, strings.Join(i.params, ", "), root.name)
+		fmt.Fprint(w, i.body.String())
+		fmt.Fprintf(w, "return %s\n", result)
+	}
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatInjectorName(t *testing.T) {
+	assert.Equal(t, "InitializeApp", formatInjectorName("app"))
+}
+
+func TestInjectorUniqueName(t *testing.T) {
+	i := newInjector(nil)
+	assert.Equal(t, "repo", i.uniqueName("repo"))
+	assert.Equal(t, "repo2", i.uniqueName("repo"))
+	assert.Equal(t, "repo3", i.uniqueName("repo"))
+}
+
+func TestInjectorBuild(t *testing.T) {
+	t.Run("cycle", func(t *testing.T) {
+		structs := []genStruct{
+			{name: "A", fields: []genField{{name: "B", typ: "B", ptr: true}}},
+			{name: "B", fields: []genField{{name: "A", typ: "A", ptr: true, optional: true}}},
+		}
+		assert.PanicsWithValue(t, "dependency cycle detected: A -> B -> A", func() {
+			newInjector(structs).build("A", nil)
+		})
+	})
+
+	t.Run("missing provider", func(t *testing.T) {
+		structs := []genStruct{
+			{name: "App", fields: []genField{{name: "Repo", typ: "Repo", ptr: true}}},
+			{name: "Repo", comment: &genComment{value: "fmgen:-"}},
+		}
+		assert.Panics(t, func() {
+			newInjector(structs).build("App", nil)
+		})
+	})
+}
+
+func TestWriteInjector(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		structs := []genStruct{
+			{name: "Repo", fields: []genField{{name: "Cache", typ: "map[string]int", mapped: true}}},
+			{name: "App", fields: []genField{{name: "Repo", typ: "Repo", ptr: true}}},
+		}
+		writeInjector(&buf, structs[1], structs)
+
+		expected := `// InitializeApp generated injector creating App along with its dependencies
+func InitializeApp(repoCache map[string]int) (*App, error) {
+repo, err := NewRepo(repoCache)
+if err != nil {
+return nil, err
+}
+app := NewApp(Repo{})
+app.Repo = repo
+return app, nil
+}
+
+`
+		assert.Equal(t, expected, buf.String())
+	})
+}
+
+func TestWritePackageFileInjector(t *testing.T) {
+	buf := bytes.Buffer{}
+	structs := []genStruct{
+		{
+			name: "Config",
+			fields: []genField{
+				{name: "DSN", typ: "string", param: "dsn"},
+			},
+		},
+		{
+			name: "Repo",
+			fields: []genField{
+				{name: "Config", typ: "Config", ptr: true},
+			},
+		},
+		{
+			name: "App",
+			fields: []genField{
+				{name: "Repo", typ: "Repo", ptr: true, optional: true},
+				{name: "Config", typ: "Config", ptr: true, optional: true},
+				{name: "Tags", typ: "string", array: true, optional: true},
+				{name: "Port", typ: "int"},
+			},
+			comment: &genComment{value: "fmgen:inject"},
+		},
+	}
+
+	expected := `// Code generated by "fmgen". DO NOT EDIT.
+package testdata
+
+// NewConfig generated factory method for Config
+//
+// Parameters:
+//   - dsn
+func NewConfig(dsn string) *Config {
+	result := &Config{
+		DSN: dsn,
+	}
+	return result
+}
+
+// NewRepo generated factory method for Repo
+//
+// Parameters:
+//   - Config
+func NewRepo(Config Config) *Repo {
+	result := &Repo{
+		Config: &Config,
+	}
+	return result
+}
+
+// NewApp generated factory method for App
+//
+// Parameters:
+//   - Port
+//   - Repo: optional, nil leaves Repo unset
+//   - Config: optional, nil leaves Config unset
+//   - Tags: optional, nil leaves Tags unset
+func NewApp(Port int, Repo *Repo, Config *Config, Tags []string) *App {
+	result := &App{
+		Port: Port,
+	}
+	if Repo != nil {
+		result.Repo = Repo
+	}
+	if Config != nil {
+		result.Config = Config
+	}
+	if Tags != nil {
+		result.Tags = Tags
+	}
+	return result
+}
+
+// InitializeApp generated injector creating App along with its dependencies
+func InitializeApp(appPort int, configDSN string) *App {
+	config := NewConfig(configDSN)
+	repo := NewRepo(Config{})
+	repo.Config = config
+	app := NewApp(appPort, repo, config, nil)
+	return app
+}
+`
+
+	writePackageFile(&buf, "testdata", []string{}, structs, nil, nil)
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestInjectorSharesDependencies(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping compiling the generated injector in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is required to compile the generated injector")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module injecttest\n\ngo 1.18\n",
+		"app.go": `package injecttest
+
+type Repo struct {
+	DSN string
+}
+
+type Service struct {
+	Repo *Repo
+}
+
+// fmgen:inject
+type App struct {
+	Svc  *Service
+	Repo *Repo
+}
+`,
+		"app_test.go": `package injecttest
+
+import "testing"
+
+func TestShared(t *testing.T) {
+	app := InitializeApp("dsn")
+	if app.Repo != app.Svc.Repo {
+		t.Fatal("expected App and Service to share the same Repo")
+	}
+}
+`,
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	run(dir, false, "")
+
+	for _, args := range [][]string{{"vet", "."}, {"test", "."}} {
+		cmd := exec.Command(goBin, args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(out))
+	}
+}
@@ -10,15 +10,56 @@ var (
 	flagFile      = flag.String("f", "", "generate factory methods only for file specific")
 	flagStructs   = flag.String("s", "", "comma separated list of structs to generate factory methods for")
 	flagVerbose   = flag.Bool("v", false, "verbose output")
+	flagFixtures  = flag.Bool("fixtures", false, "generate deterministic test fixtures for all structs")
+	flagQuick     = flag.Bool("quick", false, "generate testing/quick generators for all structs")
+	flagFuzz      = flag.Bool("fuzz", false, "generate fuzz tests for all factory methods")
+	flagTests     = flag.Bool("tests", false, "generate unit tests for all factory methods")
+	flagExamples  = flag.Bool("examples", false, "generate godoc examples for all factory methods")
+	flagFakes     = flag.Bool("fakes", false, "generate fake implementations for all interfaces")
+	flagCopy      = flag.Bool("copy", false, "copy array and map parameters in all factory methods")
+	flagFactory   = flag.String("factory", "", "generate a factory struct with the name, filling fields from its providers")
+	flagName      = flag.String("name", "New{{.Name}}", "template of the factory method names, {{.Name}} is the type name")
 )
 
 // to allow for testing
 var createGeneratedFileFunc = createGeneratedFile
+var createFixtureFileFunc = createFixtureFile
+var createQuickFileFunc = createQuickFile
+var createFuzzFileFunc = createFuzzFile
+var createTestsFileFunc = createTestsFile
+var createExamplesFileFunc = createExamplesFile
+var createFakeFileFunc = createFakeFile
+var createFactoryFileFunc = createFactoryFile
+
+func generate(dirname, pkg string, imports []string, structs []genStruct, interfaces []genInterface, genTypes []genType) {
+	createGeneratedFileFunc(dirname, pkg, imports, structs, interfaces, genTypes)
+	if *flagFixtures {
+		createFixtureFileFunc(dirname, pkg, imports, structs)
+	}
+	if *flagQuick {
+		createQuickFileFunc(dirname, pkg, imports, structs)
+	}
+	if *flagFuzz {
+		createFuzzFileFunc(dirname, pkg, imports, structs)
+	}
+	if *flagTests {
+		createTestsFileFunc(dirname, pkg, imports, structs)
+	}
+	if *flagExamples {
+		createExamplesFileFunc(dirname, pkg, imports, structs)
+	}
+	if *flagFactory != "" {
+		createFactoryFileFunc(dirname, pkg, imports, structs)
+	}
+	if *flagFakes {
+		createFakeFileFunc(dirname, pkg, imports, interfaces)
+	}
+}
 
 func run(directory string, recurse bool, file string) {
 	if file != "" {
 		parsed := parseFile(file)
-		createGeneratedFileFunc(parsed.dirname, parsed.pkg, parsed.imports, parsed.structs)
+		generate(parsed.dirname, parsed.pkg, parsed.imports, parsed.structs, parsed.interfaces, parsed.types)
 	} else {
 		var pkgs []genPackage
 		if recurse {
@@ -27,7 +68,7 @@ func run(directory string, recurse bool, file string) {
 			pkgs = parseDir(directory)
 		}
 		for _, pkg := range pkgs {
-			createGeneratedFileFunc(pkg.dirname, pkg.pkg, pkg.imports, pkg.structs)
+			generate(pkg.dirname, pkg.pkg, pkg.imports, pkg.structs, pkg.interfaces, pkg.types)
 		}
 	}
 }
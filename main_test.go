@@ -8,11 +8,18 @@ import (
 func TestRun(t *testing.T) {
 	defer func() {
 		createGeneratedFileFunc = createGeneratedFile
+		createFixtureFileFunc = createFixtureFile
+		createQuickFileFunc = createQuickFile
+		createFuzzFileFunc = createFuzzFile
+		createTestsFileFunc = createTestsFile
+		createExamplesFileFunc = createExamplesFile
+		createFakeFileFunc = createFakeFile
+		createFactoryFileFunc = createFactoryFile
 	}()
 
 	t.Run("file", func(t *testing.T) {
 		var executed bool
-		createGeneratedFileFunc = func(dirname, pkg string, imports []string, structs []genStruct) {
+		createGeneratedFileFunc = func(dirname, pkg string, imports []string, structs []genStruct, interfaces []genInterface, genTypes []genType) {
 			assert.Equal(t, "testdata", pkg)
 			assert.Equal(t, "testdata/", dirname)
 			executed = true
@@ -23,7 +30,7 @@ func TestRun(t *testing.T) {
 
 	t.Run("recurse directory", func(t *testing.T) {
 		var runCnt int
-		createGeneratedFileFunc = func(dirname, pkg string, imports []string, structs []genStruct) {
+		createGeneratedFileFunc = func(dirname, pkg string, imports []string, structs []genStruct, interfaces []genInterface, genTypes []genType) {
 			runCnt++
 		}
 		run("testdata/", true, "")
@@ -32,10 +39,107 @@ func TestRun(t *testing.T) {
 
 	t.Run("directory", func(t *testing.T) {
 		var runCnt int
-		createGeneratedFileFunc = func(dirname, pkg string, imports []string, structs []genStruct) {
+		createGeneratedFileFunc = func(dirname, pkg string, imports []string, structs []genStruct, interfaces []genInterface, genTypes []genType) {
 			runCnt++
 		}
 		run("testdata/", false, "")
 		assert.Equal(t, 1, runCnt)
 	})
+
+	// each mode is generated along with the factory methods when its flag is set
+	modes := []struct {
+		name   string
+		enable func()
+		stub   func(t *testing.T, ran func())
+	}{
+		{
+			name:   "fixtures",
+			enable: func() { *flagFixtures = true },
+			stub: func(t *testing.T, ran func()) {
+				createFixtureFileFunc = func(dirname, pkg string, imports []string, structs []genStruct) {
+					assert.Equal(t, "testdata", pkg)
+					ran()
+				}
+			},
+		},
+		{
+			name:   "quick",
+			enable: func() { *flagQuick = true },
+			stub: func(t *testing.T, ran func()) {
+				createQuickFileFunc = func(dirname, pkg string, imports []string, structs []genStruct) {
+					assert.Equal(t, "testdata", pkg)
+					ran()
+				}
+			},
+		},
+		{
+			name:   "fuzz",
+			enable: func() { *flagFuzz = true },
+			stub: func(t *testing.T, ran func()) {
+				createFuzzFileFunc = func(dirname, pkg string, imports []string, structs []genStruct) {
+					assert.Equal(t, "testdata", pkg)
+					ran()
+				}
+			},
+		},
+		{
+			name:   "tests",
+			enable: func() { *flagTests = true },
+			stub: func(t *testing.T, ran func()) {
+				createTestsFileFunc = func(dirname, pkg string, imports []string, structs []genStruct) {
+					assert.Equal(t, "testdata", pkg)
+					ran()
+				}
+			},
+		},
+		{
+			name:   "examples",
+			enable: func() { *flagExamples = true },
+			stub: func(t *testing.T, ran func()) {
+				createExamplesFileFunc = func(dirname, pkg string, imports []string, structs []genStruct) {
+					assert.Equal(t, "testdata", pkg)
+					ran()
+				}
+			},
+		},
+		{
+			name:   "fakes",
+			enable: func() { *flagFakes = true },
+			stub: func(t *testing.T, ran func()) {
+				createFakeFileFunc = func(dirname, pkg string, imports []string, interfaces []genInterface) {
+					assert.Equal(t, "testdata", pkg)
+					assert.Len(t, interfaces, 1)
+					ran()
+				}
+			},
+		},
+		{
+			name:   "factory",
+			enable: func() { *flagFactory = "Factory" },
+			stub: func(t *testing.T, ran func()) {
+				createFactoryFileFunc = func(dirname, pkg string, imports []string, structs []genStruct) {
+					assert.Equal(t, "testdata", pkg)
+					ran()
+				}
+			},
+		},
+	}
+
+	for _, m := range modes {
+		t.Run(m.name, func(t *testing.T) {
+			m.enable()
+			defer func() {
+				*flagFixtures, *flagQuick, *flagFuzz, *flagTests, *flagExamples, *flagFakes = false, false, false, false, false, false
+				*flagFactory = ""
+			}()
+
+			var runCnt int
+			createGeneratedFileFunc = func(dirname, pkg string, imports []string, structs []genStruct, interfaces []genInterface, genTypes []genType) {
+				runCnt++
+			}
+			m.stub(t, func() { runCnt++ })
+			run("testdata/", false, "")
+			assert.Equal(t, 2, runCnt)
+		})
+	}
 }
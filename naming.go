@@ -0,0 +1,86 @@
+package main
+
+import (
+	"go/token"
+	"log"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// executeName returns the factory method name from the template, {{.Name}} is replaced with the capitalized name. A
+// name without a template is returned as is
+func executeName(tmpl, name string) string {
+	t, err := template.New("name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		log.Panicf("invalid name template [%s] for [%s] - %v", tmpl, name, err)
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, struct{ Name string }{Name: capitalize(name)}); err != nil {
+		log.Panicf("invalid name template [%s] for [%s] - %v", tmpl, name, err)
+	}
+	if !token.IsIdentifier(sb.String()) {
+		log.Panicf("name template [%s] for [%s] returned [%s], which is not a valid identifier", tmpl, name, sb.String())
+	}
+	return sb.String()
+}
+
+// declaredName returns the factory method name declared with fmgen:name CreateSample or fmgen:name Make{{.Name}} in the
+// struct comment, false is returned when it isn't declared
+func (g genStruct) declaredName() (string, bool) {
+	directives := findDirectives(g.comment, "name")
+	if len(directives) == 0 {
+		return "", false
+	}
+	if len(directives) > 1 || len(directives[0].args) != 1 {
+		log.Panicf("invalid name in struct [%s], expected fmgen:name NewFunc", g.name)
+	}
+
+	tmpl := directives[0].args[0]
+	if unquoted, err := strconv.Unquote(tmpl); err == nil {
+		tmpl = unquoted
+	}
+	return executeName(tmpl, g.name), true
+}
+
+// factoryName returns the factory method name of the struct, which is the name from the -name template until names are
+// assigned
+func (g genStruct) factoryName() string {
+	if g.funcName != "" {
+		return g.funcName
+	}
+	return formatStructName(g.name)
+}
+
+// companionName returns the name of a function generated along with the factory method of the struct, named by
+// replacing the struct name within the factory method name, e.g. CreateSample names CreateSampleFactory. The -name
+// template is used when the factory method name doesn't contain the struct name
+func companionName(g genStruct, name string) string {
+	fmFuncName := g.factoryName()
+	if idx := strings.Index(fmFuncName, capitalize(g.name)); idx >= 0 {
+		return fmFuncName[:idx] + capitalize(name) + fmFuncName[idx+len(g.name):]
+	}
+	return formatStructName(name)
+}
+
+// assignNames assigns the factory method name of each struct, declared in its comment or from the -name template. Two
+// structs can't share the same name
+func assignNames(structs []genStruct) []genStruct {
+	seen := make(map[string]string)
+	result := make([]genStruct, 0, len(structs))
+	for _, s := range structs {
+		name, ok := s.declaredName()
+		if !ok {
+			name = formatStructName(s.name)
+		}
+		if other, ok := seen[name]; ok {
+			log.Panicf("structs [%s] and [%s] have the same factory method name %s", other, s.name, name)
+		}
+		seen[name] = s.name
+
+		s.funcName = name
+		result = append(result, s)
+	}
+	return result
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExecuteName(t *testing.T) {
+	assert.Equal(t, "NewSample", executeName("New{{.Name}}", "sample"))
+	assert.Equal(t, "SampleFrom", executeName("{{.Name}}From", "Sample"))
+	assert.Equal(t, "CreateSample", executeName("CreateSample", "Sample"))
+	assert.Panics(t, func() { executeName("Make{{.Name", "Sample") })
+	assert.Panics(t, func() { executeName("Make{{.Type}}", "Sample") })
+	assert.Panics(t, func() { executeName("Make {{.Name}}", "Sample") })
+}
+
+func TestDeclaredName(t *testing.T) {
+	name, ok := genStruct{name: "Sample", comment: &genComment{value: "fmgen:name=CreateSample"}}.declaredName()
+	assert.True(t, ok)
+	assert.Equal(t, "CreateSample", name)
+
+	name, ok = genStruct{name: "Sample", comment: &genComment{value: "fmgen:name Make{{.Name}}"}}.declaredName()
+	assert.True(t, ok)
+	assert.Equal(t, "MakeSample", name)
+
+	_, ok = genStruct{name: "Sample"}.declaredName()
+	assert.False(t, ok)
+
+	assert.Panics(t, func() {
+		genStruct{name: "Sample", comment: &genComment{value: "fmgen:name"}}.declaredName()
+	})
+}
+
+func TestCompanionName(t *testing.T) {
+	assert.Equal(t, "NewSampleFactory", companionName(genStruct{name: "Sample"}, "SampleFactory"))
+	assert.Equal(t, "CreateSampleFactory", companionName(genStruct{name: "Sample", funcName: "CreateSample"}, "SampleFactory"))
+	assert.Equal(t, "SampleFactoryFrom", companionName(genStruct{name: "Sample", funcName: "SampleFrom"}, "SampleFactory"))
+	assert.Equal(t, "NewSampleFactory", companionName(genStruct{name: "Sample", funcName: "Build"}, "SampleFactory"))
+}
+
+func TestAssignNames(t *testing.T) {
+	structs := []genStruct{
+		{name: "Sample", comment: &genComment{value: "fmgen:name=CreateSample"}},
+		{name: "Other"},
+	}
+	results := assignNames(structs)
+	assert.Equal(t, "CreateSample", results[0].factoryName())
+	assert.Equal(t, "NewOther", results[1].factoryName())
+	assert.Equal(t, "NewSample", structs[0].factoryName())
+
+	assert.Panics(t, func() {
+		assignNames([]genStruct{
+			{name: "Sample", comment: &genComment{value: "fmgen:name=NewOther"}},
+			{name: "Other"},
+		})
+	})
+}
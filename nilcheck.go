@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// predeclared interface types which may be nil
+var predeclaredInterfaces = map[string]bool{"any": true, "error": true}
+
+// nilChecked returns true when a nil input for the field is rejected with an error. Required maps, channels, funcs
+// and interfaces are checked unless tagged with fmgen:"allownil", any other field tagged with fmgen:"notnil" is checked
+// as well
+func nilChecked(f genField) bool {
+	if f.skip || f.gen != "" || f.init || f.allowNil {
+		return false
+	}
+	return f.notNil || (!f.optional && (f.mapped || f.channel || f.fn || f.iface))
+}
+
+// returnsError returns true when the factory method returns an error along with the struct
+func returnsError(fields []genField) bool {
+	for _, f := range fields {
+		if nilChecked(f) || (f.conv != "" && !f.skip && f.gen == "") || (f.flatten != "" && !f.skip && returnsError(f.nested)) {
+			return true
+		}
+	}
+	return hasConstraints(fields)
+}
+
+// buildReturnType returns the result of a factory method creating the struct
+func buildReturnType(name string, fields []genField) string {
+	if returnsError(fields) {
+		return fmt.Sprintf("(*%s, error)", name)
+	}
+	return "*" + name
+}
+
+// buildReturn returns the statement returning the result of a factory method
+func buildReturn(fields []genField, result string) string {
+	if returnsError(fields) {
+		return fmt.Sprintf("return %s, nil\n", result)
+	}
+	return fmt.Sprintf("return %s\n", result)
+}
+
+// buildCall returns the statements calling the factory method and assigning the struct to the result, an error is
+// passed to onError
+func buildCall(fields []genField, result, call, onError string) string {
+	if returnsError(fields) {
+		return fmt.Sprintf("%s, err := %s\nif err != nil {\n%s}\n", result, call, onError)
+	}
+	return fmt.Sprintf("%s := %s\n", result, call)
+}
+
+// buildNilChecks returns the statements returning an error naming the input param when nil is passed in for a checked
+// field
+func buildNilChecks(funcName, name string, fields []genField) string {
+	var sb strings.Builder
+	for _, p := range ownParams(fields) {
+		f := p.field
+		if !nilChecked(f) {
+			continue
+		}
+		switch {
+		case f.optional:
+			log.Panicf("notnil tag can't be used on optional field [%s] in struct [%s]", f.name, name)
+		case f.ptr && !f.array:
+			log.Panicf("notnil tag can't be used on field [%s] in struct [%s], required pointers are passed in by value", f.name, name)
+		}
+		sb.WriteString(fmt.Sprintf("if %s == nil {\nreturn nil, fmt.Errorf(\"%s: %s must not be nil\")\n}\n", p.name, funcName, p.name))
+	}
+	return sb.String()
+}
+
+// nilCheckedValue returns an expression creating a non-nil value for a checked field, false is returned when one
+// can't be created such as for funcs and interfaces
+func nilCheckedValue(f genField) (string, bool) {
+	switch {
+	case f.array:
+		return valueType(f) + "{}", true
+	case f.mapped || f.channel:
+		return fmt.Sprintf("make(%s)", f.typ), true
+	}
+	return "", false
+}
+
+// uncreatableParam returns the name of the first checked param a non-nil value can't be created for
+func uncreatableParam(s genStruct) (string, bool) {
+	for _, p := range factoryParams(s.fields) {
+		if !nilChecked(p.field) {
+			continue
+		}
+		if _, ok := nilCheckedValue(p.field); !ok {
+			return p.name, true
+		}
+	}
+	return "", false
+}
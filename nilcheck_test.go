@@ -0,0 +1,76 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNilChecked(t *testing.T) {
+	assert.True(t, nilChecked(genField{typ: "map[string]int", mapped: true}))
+	assert.True(t, nilChecked(genField{typ: "chan int", channel: true}))
+	assert.True(t, nilChecked(genField{typ: "func()", fn: true}))
+	assert.True(t, nilChecked(genField{typ: "Logger", iface: true}))
+	assert.True(t, nilChecked(genField{typ: "io.Writer", notNil: true}))
+
+	assert.False(t, nilChecked(genField{typ: "Repo", ptr: true}))
+	assert.False(t, nilChecked(genField{typ: "string", array: true}))
+	assert.False(t, nilChecked(genField{typ: "Logger", iface: true, optional: true}))
+	assert.False(t, nilChecked(genField{typ: "Logger", iface: true, allowNil: true}))
+	assert.False(t, nilChecked(genField{typ: "map[string]int", mapped: true, init: true}))
+	assert.False(t, nilChecked(genField{typ: "map[string]int", mapped: true, skip: true}))
+}
+
+func TestBuildReturnType(t *testing.T) {
+	fields := []genField{{name: "Name", typ: "string"}}
+	assert.Equal(t, "*Sample", buildReturnType("Sample", fields))
+	assert.Equal(t, "return result\n", buildReturn(fields, "result"))
+	assert.Equal(t, "result := NewSample(Name)\n", buildCall(fields, "result", "NewSample(Name)", "return nil, err\n"))
+
+	fields = append(fields, genField{name: "Logger", typ: "Logger", iface: true})
+	assert.Equal(t, "(*Sample, error)", buildReturnType("Sample", fields))
+	assert.Equal(t, "return result, nil\n", buildReturn(fields, "result"))
+	assert.Equal(t, "result, err := NewSample(Name, Logger)\nif err != nil {\nreturn nil, err\n}\n",
+		buildCall(fields, "result", "NewSample(Name, Logger)", "return nil, err\n"))
+}
+
+func TestBuildNilChecks(t *testing.T) {
+	t.Run("checked", func(t *testing.T) {
+		fields := []genField{
+			{name: "Name", typ: "string"},
+			{name: "Logger", typ: "Logger", iface: true},
+			{name: "Out", typ: "io.Writer", notNil: true},
+			{name: "Cache", typ: "map[string]int", mapped: true, optional: true},
+		}
+		expected := "if Logger == nil {\nreturn nil, fmt.Errorf(\"NewSample: Logger must not be nil\")\n}\n" +
+			"if Out == nil {\nreturn nil, fmt.Errorf(\"NewSample: Out must not be nil\")\n}\n"
+		assert.Equal(t, expected, buildNilChecks("NewSample", "Sample", fields))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Panics(t, func() {
+			buildNilChecks("NewSample", "Sample", []genField{{name: "Repo", typ: "Repo", ptr: true, notNil: true}})
+		})
+		assert.Panics(t, func() {
+			buildNilChecks("NewSample", "Sample", []genField{{name: "Out", typ: "io.Writer", optional: true, notNil: true}})
+		})
+	})
+}
+
+func TestUncreatableParam(t *testing.T) {
+	s := genStruct{name: "Sample", fields: []genField{
+		{name: "Cache", typ: "map[string]int", mapped: true},
+		{name: "Tags", typ: "string", array: true, notNil: true},
+	}}
+	_, ok := uncreatableParam(s)
+	assert.False(t, ok)
+
+	s.fields = append(s.fields, genField{name: "Logger", typ: "Logger", iface: true})
+	name, ok := uncreatableParam(s)
+	assert.True(t, ok)
+	assert.Equal(t, "Logger", name)
+
+	value, _ := nilCheckedValue(s.fields[0])
+	assert.Equal(t, "make(map[string]int)", value)
+	value, _ = nilCheckedValue(s.fields[1])
+	assert.Equal(t, "[]string{}", value)
+}
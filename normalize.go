@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	normalizeTrim   = "trim"
+	normalizeLower  = "lower"
+	normalizeUpper  = "upper"
+	normalizeUTC    = "utc"
+	normalizeRound  = "round"
+	normalizeDedupe = "dedupe"
+	normalizeSort   = "sort"
+	normalizeCall   = "call"
+)
+
+var normalizeTags = []string{
+	normalizeTrim, normalizeLower, normalizeUpper, normalizeUTC, normalizeRound, normalizeDedupe, normalizeSort,
+	normalizeCall,
+}
+
+// types which can be sorted with the < operator
+var sortableTypes = map[string]bool{
+	"string": true, "byte": true, "rune": true, "float32": true, "float64": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+}
+
+// durationExpr returns the duration as an expression using the largest unit it is a multiple of, e.g. 1ms is
+// returned as 1 * time.Millisecond
+func durationExpr(d time.Duration) string {
+	units := []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.d == 0 {
+			return fmt.Sprintf("%d * %s", d/u.d, u.name)
+		}
+	}
+	return fmt.Sprintf("%d * time.Nanosecond", d)
+}
+
+// normalizeValue returns the expression applying the normalizer to a single value of the field
+func normalizeValue(name string, f genField, normalizer, value string) string {
+	split := strings.SplitN(normalizer, "=", 2)
+	key, arg := split[0], ""
+	if len(split) > 1 {
+		arg = split[1]
+	}
+	requireType := func(typ string) {
+		if f.typ != typ || (f.array && f.ptr) {
+			log.Panicf("%s tag requires a %s field, [%s] in struct [%s] is %s", key, typ, f.name, name, valueType(f))
+		}
+	}
+
+	switch key {
+	case normalizeTrim:
+		requireType("string")
+		return fmt.Sprintf("strings.TrimSpace(%s)", value)
+	case normalizeLower:
+		requireType("string")
+		return fmt.Sprintf("strings.ToLower(%s)", value)
+	case normalizeUpper:
+		requireType("string")
+		return fmt.Sprintf("strings.ToUpper(%s)", value)
+	case normalizeUTC:
+		requireType("time.Time")
+		return fmt.Sprintf("%s.UTC()", value)
+	case normalizeRound:
+		requireType("time.Time")
+		d, err := time.ParseDuration(arg)
+		if err != nil || d <= 0 {
+			log.Panicf("invalid round duration [%s] for field [%s] in struct [%s]", arg, f.name, name)
+		}
+		return fmt.Sprintf("%s.Round(%s)", value, durationExpr(d))
+	case normalizeCall:
+		if arg == "" {
+			log.Panicf("call tag is missing a function for field [%s] in struct [%s]", f.name, name)
+		}
+		if f.array && f.ptr {
+			log.Panicf("call tag is not supported on array of pointers field [%s] in struct [%s]", f.name, name)
+		}
+		return fmt.Sprintf("%s(%s)", arg, value)
+	}
+	return value
+}
+
+// buildNormalizeArray returns the statements normalizing each element of the array param, then removing duplicates
+// and sorting it. A new array is always created so the caller's array is never modified
+func buildNormalizeArray(name string, f genField, normalizers []string, dedupe, sorted bool) string {
+	var sb strings.Builder
+	param := f.paramName()
+	typ := valueType(f)
+	elem := strings.TrimPrefix(typ, "[]")
+
+	sb.WriteString(fmt.Sprintf("if %s != nil {\n", param))
+	switch {
+	case len(normalizers) > 0:
+		value := "v"
+		for _, n := range normalizers {
+			value = normalizeValue(name, f, n, value)
+		}
+		sb.WriteString(fmt.Sprintf("normalized := make(%s, len(%s))\nfor i, v := range %s {\nnormalized[i] = %s\n}\n%s = normalized\n", typ, param, param, value, param))
+	case !dedupe:
+		sb.WriteString(fmt.Sprintf("%s = append(%s(nil), %s...)\n", param, typ, param))
+	}
+
+	if dedupe {
+		if !f.ptr && (strings.HasPrefix(f.typ, "[]") || strings.HasPrefix(f.typ, "map[")) {
+			log.Panicf("dedupe tag is not supported on field [%s] of type [%s] in struct [%s]", f.name, typ, name)
+		}
+		sb.WriteString(fmt.Sprintf("seen := make(map[%s]bool, len(%s))\ndeduped := make(%s, 0, len(%s))\n", elem, param, typ, param))
+		sb.WriteString(fmt.Sprintf("for _, v := range %s {\nif !seen[v] {\nseen[v] = true\ndeduped = append(deduped, v)\n}\n}\n", param))
+		sb.WriteString(fmt.Sprintf("%s = deduped\n", param))
+	}
+
+	if sorted {
+		if f.ptr || !sortableTypes[f.typ] {
+			log.Panicf("sort tag is not supported on field [%s] of type [%s] in struct [%s]", f.name, typ, name)
+		}
+		sb.WriteString(fmt.Sprintf("sort.Slice(%s, func(i, j int) bool {\nreturn %s[i] < %s[j]\n})\n", param, param, param))
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// buildNormalize returns the statements normalizing each input param with the tags of its field, the params are
+// replaced with the normalized values before they are assigned
+func buildNormalize(name string, fields []genField) string {
+	var sb strings.Builder
+	for _, p := range ownParams(fields) {
+		f := p.field
+		if len(f.normalize) == 0 {
+			continue
+		}
+
+		var normalizers []string
+		var dedupe, sorted bool
+		for _, n := range f.normalize {
+			switch n {
+			case normalizeDedupe, normalizeSort:
+				if !f.array {
+					log.Panicf("%s tag requires an array field, [%s] in struct [%s] is %s", n, f.name, name, valueType(f))
+				}
+				dedupe = dedupe || n == normalizeDedupe
+				sorted = sorted || n == normalizeSort
+			default:
+				normalizers = append(normalizers, n)
+			}
+		}
+
+		if f.array {
+			sb.WriteString(buildNormalizeArray(name, f, normalizers, dedupe, sorted))
+			continue
+		}
+
+		param := f.paramName()
+		value := param
+		if f.optional && !f.mapped {
+			value = "v"
+		}
+		for _, n := range normalizers {
+			value = normalizeValue(name, f, n, value)
+		}
+
+		switch {
+		case f.mapped:
+			sb.WriteString(fmt.Sprintf("if %s != nil {\n%s = %s\n}\n", param, param, value))
+		case f.optional:
+			// the value the caller points to is not modified
+			sb.WriteString(fmt.Sprintf("if %s != nil {\nv := *%s\nv = %s\n%s = &v\n}\n", param, param, value, param))
+		default:
+			sb.WriteString(fmt.Sprintf("%s = %s\n", param, value))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDurationExpr(t *testing.T) {
+	assert.Equal(t, "1 * time.Millisecond", durationExpr(time.Millisecond))
+	assert.Equal(t, "90 * time.Second", durationExpr(90*time.Second))
+	assert.Equal(t, "2 * time.Hour", durationExpr(2*time.Hour))
+	assert.Equal(t, "1500 * time.Nanosecond", durationExpr(1500*time.Nanosecond))
+}
+
+func TestBuildNormalize(t *testing.T) {
+	t.Run("strings", func(t *testing.T) {
+		fields := []genField{
+			{name: "Email", typ: "string", normalize: []string{"trim", "lower", "call=normalizeEmail"}},
+			{name: "Code", typ: "string", optional: true, normalize: []string{"upper"}},
+			{name: "Name", typ: "string"},
+		}
+		expected := "Email = normalizeEmail(strings.ToLower(strings.TrimSpace(Email)))\n" +
+			"if Code != nil {\nv := *Code\nv = strings.ToUpper(v)\nCode = &v\n}\n"
+		assert.Equal(t, expected, buildNormalize("Sample", fields))
+	})
+
+	t.Run("time", func(t *testing.T) {
+		fields := []genField{
+			{name: "Created", typ: "time.Time", ptr: true, normalize: []string{"utc", "round=1ms"}},
+		}
+		assert.Equal(t, "Created = Created.UTC().Round(1 * time.Millisecond)\n", buildNormalize("Sample", fields))
+	})
+
+	t.Run("arrays", func(t *testing.T) {
+		fields := []genField{
+			{name: "Tags", typ: "string", array: true, normalize: []string{"lower", "dedupe", "sort"}},
+			{name: "Scores", typ: "int", array: true, optional: true, normalize: []string{"sort"}},
+		}
+		expected := "if Tags != nil {\n" +
+			"normalized := make([]string, len(Tags))\nfor i, v := range Tags {\nnormalized[i] = strings.ToLower(v)\n}\nTags = normalized\n" +
+			"seen := make(map[string]bool, len(Tags))\ndeduped := make([]string, 0, len(Tags))\n" +
+			"for _, v := range Tags {\nif !seen[v] {\nseen[v] = true\ndeduped = append(deduped, v)\n}\n}\nTags = deduped\n" +
+			"sort.Slice(Tags, func(i, j int) bool {\nreturn Tags[i] < Tags[j]\n})\n}\n" +
+			"if Scores != nil {\nScores = append([]int(nil), Scores...)\n" +
+			"sort.Slice(Scores, func(i, j int) bool {\nreturn Scores[i] < Scores[j]\n})\n}\n"
+		assert.Equal(t, expected, buildNormalize("Sample", fields))
+	})
+
+	t.Run("skipped and computed", func(t *testing.T) {
+		fields := []genField{
+			{name: "ID", typ: "string", skip: true, normalize: []string{"trim"}},
+			{name: "Created", typ: "time.Time", gen: "time.Now", normalize: []string{"utc"}},
+		}
+		assert.Empty(t, buildNormalize("Sample", fields))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Panics(t, func() {
+			buildNormalize("Sample", []genField{{name: "Age", typ: "int", normalize: []string{"trim"}}})
+		})
+		assert.Panics(t, func() {
+			buildNormalize("Sample", []genField{{name: "Name", typ: "string", normalize: []string{"sort"}}})
+		})
+		assert.Panics(t, func() {
+			buildNormalize("Sample", []genField{{name: "Created", typ: "time.Time", normalize: []string{"round=soon"}}})
+		})
+		assert.Panics(t, func() {
+			buildNormalize("Sample", []genField{{name: "Children", typ: "Sample", array: true, ptr: true, normalize: []string{"sort"}}})
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"log"
+	"strconv"
+)
+
+const (
+	orderDeclaration  = "declaration"
+	orderRequired     = "required"
+	orderAlphabetical = "alphabetical"
+)
+
+// orderPolicy returns the order of the factory method params declared with fmgen:order, params are ordered with
+// required fields first when it isn't declared
+func (g genStruct) orderPolicy() string {
+	policy := orderRequired
+	for _, d := range findDirectives(g.comment, "order") {
+		if len(d.args) != 1 {
+			log.Panicf("invalid order in struct [%s], expected one of %s, %s or %s", g.name, orderDeclaration, orderRequired, orderAlphabetical)
+		}
+		switch d.args[0] {
+		case orderDeclaration, orderRequired, orderAlphabetical:
+			policy = d.args[0]
+		default:
+			log.Panicf("unknown order [%s] in struct [%s], expected one of %s, %s or %s", d.args[0], g.name, orderDeclaration, orderRequired, orderAlphabetical)
+		}
+	}
+	return policy
+}
+
+// orderIndex returns the position of a field tagged with fmgen:"order=N", or 0 when it isn't tagged
+func orderIndex(f genField) int {
+	n, _ := strconv.Atoi(f.order)
+	return n
+}
+
+// paramLess returns true when the param of field a comes before the param of field b. Fields tagged with
+// fmgen:"order=N" come first in ascending order, followed by the other fields in the order of the struct policy
+func paramLess(a, b genField) bool {
+	ai, bi := orderIndex(a), orderIndex(b)
+	if ai > 0 || bi > 0 {
+		if ai > 0 && bi > 0 {
+			return ai < bi
+		}
+		return ai > 0
+	}
+
+	switch a.orderBy {
+	case orderDeclaration:
+		return false
+	case orderAlphabetical:
+		return a.name < b.name
+	}
+	return bool2int(a.optional) < bool2int(b.optional)
+}
+
+// assignOrder assigns the order policy of each struct to its fields, the order tags are validated so params keep the
+// same order across regenerations
+func assignOrder(structs []genStruct) []genStruct {
+	result := make([]genStruct, 0, len(structs))
+	for _, s := range structs {
+		policy := s.orderPolicy()
+		seen := make(map[int]string)
+		fields := make([]genField, len(s.fields))
+		for i, f := range s.fields {
+			if f.order != "" {
+				n, err := strconv.Atoi(f.order)
+				if err != nil || n <= 0 {
+					log.Panicf("invalid order [%s] for field [%s] in struct [%s], expected a positive number", f.order, f.name, s.name)
+				}
+				if other, ok := seen[n]; ok {
+					log.Panicf("fields [%s] and [%s] in struct [%s] have the same order %d", other, f.name, s.name, n)
+				}
+				seen[n] = f.name
+			}
+			f.orderBy = policy
+			fields[i] = f
+		}
+		s.fields = fields
+		result = append(result, s)
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOrderPolicy(t *testing.T) {
+	assert.Equal(t, "required", genStruct{name: "Sample"}.orderPolicy())
+	assert.Equal(t, "alphabetical", genStruct{name: "Sample", comment: &genComment{value: "fmgen:order alphabetical"}}.orderPolicy())
+	assert.Panics(t, func() {
+		genStruct{name: "Sample", comment: &genComment{value: "fmgen:order random"}}.orderPolicy()
+	})
+	assert.Panics(t, func() {
+		genStruct{name: "Sample", comment: &genComment{value: "fmgen:order"}}.orderPolicy()
+	})
+}
+
+func TestAssignOrder(t *testing.T) {
+	fields := []genField{
+		{name: "Name", typ: "string", optional: true},
+		{name: "Email", typ: "string"},
+		{name: "ID", typ: "string", order: "1"},
+		{name: "Age", typ: "int"},
+	}
+	names := func(s genStruct) []string {
+		var result []string
+		for _, p := range factoryParams(assignOrder([]genStruct{s})[0].fields) {
+			result = append(result, p.name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"ID", "Email", "Age", "Name"}, names(genStruct{name: "Sample", fields: fields}))
+	assert.Equal(t, []string{"ID", "Name", "Email", "Age"}, names(genStruct{name: "Sample", fields: fields, comment: &genComment{value: "fmgen:order declaration"}}))
+	assert.Equal(t, []string{"ID", "Age", "Email", "Name"}, names(genStruct{name: "Sample", fields: fields, comment: &genComment{value: "fmgen:order alphabetical"}}))
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Panics(t, func() {
+			assignOrder([]genStruct{{name: "Sample", fields: []genField{{name: "ID", typ: "string", order: "first"}}}})
+		})
+		assert.Panics(t, func() {
+			assignOrder([]genStruct{{name: "Sample", fields: []genField{{name: "ID", typ: "string", order: "0"}}}})
+		})
+		assert.Panics(t, func() {
+			assignOrder([]genStruct{{name: "Sample", fields: []genField{
+				{name: "ID", typ: "string", order: "1"},
+				{name: "Name", typ: "string", order: "1"},
+			}}})
+		})
+	})
+}
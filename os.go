@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	"go/parser"
 	"go/token"
+	"io"
 	"io/fs"
 	"io/ioutil"
 	"log"
@@ -16,11 +17,21 @@ import (
 
 const (
 	generatedFileName = "fm_gen.go"
+	fixtureFileName   = "fm_fixture_test.go"
+	quickFileName     = "fm_quick_test.go"
+	fuzzFileName      = "fm_fuzz_test.go"
+	testsFileName     = "fm_gen_test.go"
+	examplesFileName  = "fm_example_test.go"
+	fakeFileName      = "fm_fake.go"
+	factoryFileName   = "fm_factory.go"
 )
 
 // allow overriding to simplify testing
 var parseStructsFunc = parseStructs
 var parsedImportsFunc = parseImports
+var parseInterfacesFunc = parseInterfaces
+var parseMethodsFunc = parseMethods
+var parseTypesFunc = parseTypes
 
 func parseAllDirs(dir string) []genPackage {
 	fileInfos, err := ioutil.ReadDir(dir)
@@ -45,9 +56,10 @@ func parseAllDirs(dir string) []genPackage {
 func parseDir(dir string) []genPackage {
 	fset := token.NewFileSet()
 	pkgs, err := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
-		// skip fm_gen.go and *test.go files
+		// skip fm_gen.go, fm_fake.go, fm_factory.go and *test.go files
 		filename := info.Name()
-		return filename != generatedFileName && !strings.HasSuffix(filename, "test.go")
+		return filename != generatedFileName && filename != fakeFileName && filename != factoryFileName &&
+			!strings.HasSuffix(filename, "test.go")
 	}, parser.ParseComments)
 	if err != nil {
 		log.Panicf("unable to parse directory [%s] - %v", dir, errors.WithStack(err))
@@ -57,18 +69,37 @@ func parseDir(dir string) []genPackage {
 
 	for _, p := range pkgs {
 		parsedStructs := make([]genStruct, 0)
+		parsedInterfaces := make([]genInterface, 0)
+		parsedMethods := make([]genReceiver, 0)
+		parsedTypes := make([]genType, 0)
+		parsedConsts := make([]genConst, 0)
+		parsedFuncs := make([]string, 0)
 		parsedImports := make([]string, 0)
 		for _, file := range p.Files {
 			parsedStructs = append(parsedStructs, parseStructsFunc(fset, file)...)
+			parsedInterfaces = append(parsedInterfaces, parseInterfacesFunc(fset, file)...)
+			parsedMethods = append(parsedMethods, parseMethodsFunc(file)...)
+			parsedTypes = append(parsedTypes, parseTypesFunc(fset, file)...)
+			parsedConsts = append(parsedConsts, parseConsts(file)...)
+			parsedFuncs = append(parsedFuncs, parseFuncs(file)...)
 			parsedImports = append(parsedImports, parsedImportsFunc(file)...)
 		}
+		parsedStructs = assignMethods(parsedStructs, parsedMethods)
+		parsedStructs = assignInterfaces(parsedStructs, parsedInterfaces)
+		parsedStructs = assignOrder(parsedStructs)
+		parsedStructs = assignParams(parsedStructs, parsedImports)
+		parsedStructs = assignNames(parsedStructs)
+		parsedStructs = assignNested(parsedStructs)
+		parsedTypes = assignDeclared(assignConsts(parsedTypes, parsedConsts), parsedFuncs, parsedMethods)
 
 		result = append(result, genPackage{
-			dirname: dir,
-			pkg:     p.Name,
-			fset:    fset,
-			structs: parsedStructs,
-			imports: parsedImports,
+			dirname:    dir,
+			pkg:        p.Name,
+			fset:       fset,
+			structs:    parsedStructs,
+			interfaces: parsedInterfaces,
+			types:      parsedTypes,
+			imports:    parsedImports,
 		})
 	}
 
@@ -84,30 +115,104 @@ func parseFile(filename string) genFile {
 	}
 
 	d, f := path.Split(filename)
+	interfaces := parseInterfacesFunc(fset, file)
+	methods := parseMethodsFunc(file)
+	imports := parsedImportsFunc(file)
 
 	return genFile{
-		dirname:  d,
-		filename: f,
-		pkg:      file.Name.Name,
-		structs:  parseStructsFunc(fset, file),
-		imports:  parsedImportsFunc(file),
+		dirname:    d,
+		filename:   f,
+		pkg:        file.Name.Name,
+		structs:    assignNested(assignNames(assignParams(assignOrder(assignInterfaces(assignMethods(parseStructsFunc(fset, file), methods), interfaces)), imports))),
+		interfaces: interfaces,
+		types:      assignDeclared(assignConsts(parseTypesFunc(fset, file), parseConsts(file)), parseFuncs(file), methods),
+		imports:    imports,
 	}
 }
 
-func createGeneratedFile(dirname, pkg string, imports []string, structs []genStruct) {
-	var data bytes.Buffer
-	var writableStructs []genStruct
-
-	// filter out any structs that should be skipped
+// filter out any structs that should be skipped
+func writableStructs(structs []genStruct) []genStruct {
+	var result []genStruct
 	for _, s := range structs {
 		if !s.Skip() {
-			writableStructs = append(writableStructs, s)
+			result = append(result, s)
 		}
 	}
-	if len(writableStructs) > 0 {
-		writePackageFile(&data, pkg, imports, writableStructs)
-		if err := os.WriteFile(fmt.Sprintf("%s/%s", dirname, generatedFileName), data.Bytes(), 0644); err != nil {
-			log.Panicf("unable to write %s file to %s - %v", generatedFileName, dirname, err)
+	return result
+}
+
+func writeGeneratedFile(dirname, filename string, data []byte) {
+	if err := os.WriteFile(fmt.Sprintf("%s/%s", dirname, filename), data, 0644); err != nil {
+		log.Panicf("unable to write %s file to %s - %v", filename, dirname, err)
+	}
+}
+
+// createStructsFile writes a generated file for all writable structs, the file is only created when at least one
+// struct is writable
+func createStructsFile(dirname, filename, pkg string, imports []string, structs []genStruct, write func(io.Writer, string, []string, []genStruct)) {
+	var data bytes.Buffer
+
+	if writable := writableStructs(structs); len(writable) > 0 {
+		write(&data, pkg, imports, writable)
+		writeGeneratedFile(dirname, filename, data.Bytes())
+	}
+}
+
+// createGeneratedFile writes the factory methods for all writable structs, sealed interfaces and defined types, the
+// file is only created when at least one is writable
+func createGeneratedFile(dirname, pkg string, imports []string, structs []genStruct, interfaces []genInterface, genTypes []genType) {
+	var data bytes.Buffer
+
+	writable := len(writableStructs(structs)) > 0
+	for _, i := range interfaces {
+		writable = writable || (i.sealed() && !i.Skip())
+	}
+	for _, t := range genTypes {
+		writable = writable || (t.generated() && !t.Skip() && len(t.declared) == 0)
+	}
+
+	if writable {
+		// all structs are passed to find the variants of sealed interfaces
+		writePackageFile(&data, pkg, imports, structs, interfaces, genTypes)
+		writeGeneratedFile(dirname, generatedFileName, data.Bytes())
+	}
+}
+
+func createFixtureFile(dirname, pkg string, imports []string, structs []genStruct) {
+	createStructsFile(dirname, fixtureFileName, pkg, imports, structs, writeFixtureFile)
+}
+
+func createQuickFile(dirname, pkg string, imports []string, structs []genStruct) {
+	createStructsFile(dirname, quickFileName, pkg, imports, structs, writeQuickFile)
+}
+
+func createFuzzFile(dirname, pkg string, imports []string, structs []genStruct) {
+	createStructsFile(dirname, fuzzFileName, pkg, imports, structs, writeFuzzFile)
+}
+
+func createTestsFile(dirname, pkg string, imports []string, structs []genStruct) {
+	createStructsFile(dirname, testsFileName, pkg, imports, structs, writeTestsFile)
+}
+
+func createExamplesFile(dirname, pkg string, imports []string, structs []genStruct) {
+	createStructsFile(dirname, examplesFileName, pkg, imports, structs, writeExamplesFile)
+}
+
+func createFactoryFile(dirname, pkg string, imports []string, structs []genStruct) {
+	createStructsFile(dirname, factoryFileName, pkg, imports, structs, writeFactoryFile)
+}
+
+// createFakeFile writes fakes for all writable interfaces, the file is only created when at least one interface is
+// writable
+func createFakeFile(dirname, pkg string, imports []string, interfaces []genInterface) {
+	var data bytes.Buffer
+
+	for _, i := range interfaces {
+		if !i.Skip() {
+			// all interfaces are passed to resolve embedded interfaces
+			writeFakeFile(&data, pkg, imports, interfaces)
+			writeGeneratedFile(dirname, fakeFileName, data.Bytes())
+			return
 		}
 	}
 }
@@ -101,7 +101,7 @@ func TestCreateGeneratedFile(t *testing.T) {
 				},
 			},
 		}
-		createGeneratedFile("testdata", "testdata", []string{}, structs)
+		createGeneratedFile("testdata", "testdata", []string{}, structs, nil, nil)
 		_, err := os.Stat("testdata/fm_gen.go")
 		assert.True(t, os.IsNotExist(err))
 	})
@@ -125,7 +125,7 @@ func TestCreateGeneratedFile(t *testing.T) {
 				comment: nil,
 			},
 		}
-		createGeneratedFile("testdata", "testdata", []string{}, structs)
+		createGeneratedFile("testdata", "testdata", []string{}, structs, nil, nil)
 
 		results, err := ioutil.ReadFile("testdata/fm_gen.go")
 		assert.NoError(t, err)
@@ -134,6 +134,9 @@ func TestCreateGeneratedFile(t *testing.T) {
 package testdata
 
 // NewSimple generated factory method for Simple
+//
+// Parameters:
+//   - Name
 func NewSimple(Name string) *Simple {
     result := &Simple{
         Name: Name,
@@ -149,3 +152,217 @@ func NewSimple(Name string) *Simple {
 		assert.NoError(t, os.Remove("testdata/fm_gen.go"))
 	})
 }
+
+func TestCreateGeneratedFileSealed(t *testing.T) {
+	structs := []genStruct{
+		{
+			name:    "Created",
+			comment: &genComment{value: "fmgen:-"},
+			methods: map[string]bool{"isEvent": false},
+		},
+	}
+	interfaces := []genInterface{
+		{
+			name:    "Event",
+			methods: []genMethod{{name: "isEvent"}},
+			comment: &genComment{value: "fmgen:sealed"},
+		},
+	}
+	createGeneratedFile("testdata", "testdata", []string{}, structs, interfaces, nil)
+
+	results, err := ioutil.ReadFile("testdata/fm_gen.go")
+	assert.NoError(t, err)
+	assert.Contains(t, string(results), "func VisitEvent(v Event, onCreated func(*Created)) {")
+	assert.NotContains(t, string(results), "func NewCreated(")
+
+	assert.NoError(t, os.Remove("testdata/fm_gen.go"))
+}
+
+func TestCreateGeneratedFileDefinedTypes(t *testing.T) {
+	genTypes := []genType{
+		{
+			name:       "Status",
+			underlying: "string",
+			consts:     []string{"StatusActive"},
+			comment:    &genComment{value: "fmgen:enum"},
+		},
+	}
+	createGeneratedFile("testdata", "testdata", []string{}, nil, nil, genTypes)
+
+	results, err := ioutil.ReadFile("testdata/fm_gen.go")
+	assert.NoError(t, err)
+	assert.Contains(t, string(results), "func NewStatus(s string) (Status, error) {")
+
+	assert.NoError(t, os.Remove("testdata/fm_gen.go"))
+
+	t.Run("already declared", func(t *testing.T) {
+		genTypes[0].declared = []string{"NewStatus"}
+		createGeneratedFile("testdata", "testdata", []string{}, nil, nil, genTypes)
+		_, err := os.Stat("testdata/fm_gen.go")
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestCreateFixtureFile(t *testing.T) {
+	t.Run("validate empty []struct is skipped", func(t *testing.T) {
+		structs := []genStruct{
+			{
+				name: "Skip",
+				comment: &genComment{
+					lineNum: 1,
+					value:   "fmgen:-",
+				},
+			},
+		}
+		createFixtureFile("testdata", "testdata", []string{}, structs)
+		_, err := os.Stat("testdata/fm_fixture_test.go")
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("write file and validate", func(t *testing.T) {
+		structs := []genStruct{
+			{
+				name: "Simple",
+				fields: []genField{
+					{name: "Active", typ: "bool"},
+				},
+			},
+		}
+		createFixtureFile("testdata", "testdata", []string{}, structs)
+
+		results, err := ioutil.ReadFile("testdata/fm_fixture_test.go")
+		assert.NoError(t, err)
+		assert.Contains(t, string(results), "func NewSimpleFixture(seed int64, overrides ...func(*Simple)) *Simple {")
+
+		assert.NoError(t, os.Remove("testdata/fm_fixture_test.go"))
+	})
+}
+
+func TestCreateQuickFile(t *testing.T) {
+	structs := []genStruct{
+		{
+			name: "Simple",
+			fields: []genField{
+				{name: "Active", typ: "bool"},
+			},
+		},
+	}
+	createQuickFile("testdata", "testdata", []string{}, structs)
+
+	results, err := ioutil.ReadFile("testdata/fm_quick_test.go")
+	assert.NoError(t, err)
+	assert.Contains(t, string(results), "func (Simple) Generate(r *fmrand.Rand, size int) reflect.Value {")
+
+	assert.NoError(t, os.Remove("testdata/fm_quick_test.go"))
+}
+
+func TestCreateFuzzFile(t *testing.T) {
+	structs := []genStruct{
+		{
+			name: "Simple",
+			fields: []genField{
+				{name: "Active", typ: "bool"},
+			},
+		},
+	}
+	createFuzzFile("testdata", "testdata", []string{}, structs)
+
+	results, err := ioutil.ReadFile("testdata/fm_fuzz_test.go")
+	assert.NoError(t, err)
+	assert.Contains(t, string(results), "func FuzzNewSimple(f *testing.F) {")
+
+	assert.NoError(t, os.Remove("testdata/fm_fuzz_test.go"))
+}
+
+func TestCreateTestsFile(t *testing.T) {
+	structs := []genStruct{
+		{
+			name: "Simple",
+			fields: []genField{
+				{name: "Active", typ: "bool"},
+			},
+		},
+	}
+	createTestsFile("testdata", "testdata", []string{}, structs)
+
+	results, err := ioutil.ReadFile("testdata/fm_gen_test.go")
+	assert.NoError(t, err)
+	assert.Contains(t, string(results), "func TestNewSimple(t *testing.T) {")
+
+	assert.NoError(t, os.Remove("testdata/fm_gen_test.go"))
+}
+
+func TestCreateExamplesFile(t *testing.T) {
+	structs := []genStruct{
+		{
+			name: "Simple",
+			fields: []genField{
+				{name: "Active", typ: "bool"},
+			},
+		},
+	}
+	createExamplesFile("testdata", "testdata", []string{}, structs)
+
+	results, err := ioutil.ReadFile("testdata/fm_example_test.go")
+	assert.NoError(t, err)
+	assert.Contains(t, string(results), "func ExampleNewSimple() {")
+
+	assert.NoError(t, os.Remove("testdata/fm_example_test.go"))
+}
+
+func TestCreateFactoryFile(t *testing.T) {
+	*flagFactory = "Factory"
+	defer func() {
+		*flagFactory = ""
+	}()
+
+	structs := []genStruct{
+		{
+			name: "Simple",
+			fields: []genField{
+				{name: "Active", typ: "bool"},
+			},
+		},
+	}
+	createFactoryFile("testdata", "testdata", []string{}, structs)
+
+	results, err := ioutil.ReadFile("testdata/fm_factory.go")
+	assert.NoError(t, err)
+	assert.Contains(t, string(results), "func (f *Factory) NewSimple(Active bool) *Simple {")
+
+	assert.NoError(t, os.Remove("testdata/fm_factory.go"))
+}
+
+func TestCreateFakeFile(t *testing.T) {
+	t.Run("validate skipped interfaces", func(t *testing.T) {
+		interfaces := []genInterface{
+			{
+				name:    "Skip",
+				methods: []genMethod{{name: "Run"}},
+				comment: &genComment{
+					lineNum: 1,
+					value:   "fmgen:-",
+				},
+			},
+		}
+		createFakeFile("testdata", "testdata", []string{}, interfaces)
+		_, err := os.Stat("testdata/fm_fake.go")
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("write file and validate", func(t *testing.T) {
+		interfaces := []genInterface{
+			{
+				name:    "iface",
+				methods: []genMethod{{name: "Run"}},
+			},
+		}
+		createFakeFile("testdata", "testdata", []string{}, interfaces)
+
+		results, err := ioutil.ReadFile("testdata/fm_fake.go")
+		assert.NoError(t, err)
+		assert.Contains(t, string(results), "func NewFakeIface(runFunc func()) *FakeIface {")
+
+		assert.NoError(t, os.Remove("testdata/fm_fake.go"))
+	})
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"log"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// locals declared by the generated factory methods, tests, fuzz tests and factories which params can't shadow
+var reservedParams = map[string]bool{
+	"result": true, "err": true, "v": true, "n": true, "c": true, "i": true, "j": true, "k": true, "seen": true,
+	"deduped": true, "normalized": true, "f": true, "r": true, "t": true, "tt": true, "tests": true, "caseName": true,
+}
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName returns the package name of an import path, e.g. "math/rand" is imported as rand unless it's named as in
+// fmrand "math/rand"
+func importName(path string) string {
+	if fields := strings.Fields(path); len(fields) == 2 {
+		return fields[0]
+	}
+	parts := strings.Split(strings.Trim(path, "\"`"), "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && majorVersion.MatchString(name) {
+		name = parts[len(parts)-2]
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// lowerCamel returns the name with its leading upper case letters lower case, initialisms such as ID, IDs and
+// HTTPServer become id, ids and httpServer
+func lowerCamel(in string) string {
+	runes := []rune(in)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	rest := string(runes[n:])
+	if n > 1 && rest != "" && rest != "s" && unicode.IsLower(runes[n]) {
+		// the last upper case letter starts the next word
+		n--
+	}
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// paramName returns the name of the input param of the field, which is the field name until params are assigned
+func (f genField) paramName() string {
+	if f.param != "" {
+		return f.param
+	}
+	return f.name
+}
+
+// flattenedName returns the name of a param of a flattened field, prefixed with the param of the field, e.g. the
+// field Geo.Lat of the field Home is passed in as homeGeoLat
+func flattenedName(f genField, p genParam) string {
+	var sb strings.Builder
+	sb.WriteString(f.paramName())
+	for _, part := range strings.Split(p.field.name, ".") {
+		sb.WriteString(capitalize(part))
+	}
+	return sb.String()
+}
+
+// structParams returns the param name of each field passed in, params are the lowerCamel field names unless declared
+// with fmgen:"param=name". Names shadowing a keyword, builtin, package or generated local are suffixed with Value, then
+// numbered in the order the fields are declared
+func structParams(s genStruct, reserved map[string]bool) map[string]string {
+	taken := func(name string) bool {
+		return reserved[name] || reservedParams[name] || token.IsKeyword(name) || types.Universe.Lookup(name) != nil
+	}
+
+	params := make(map[string]string)
+	used := make(map[string]string)
+	for _, f := range s.fields {
+		if f.skip || f.gen != "" || f.param == "" {
+			continue
+		}
+		if isBuiltinType(f.param) {
+			log.Panicf("invalid param [%s] for field [%s] in struct [%s], the type of a param can only be changed with conv", f.param, f.name, s.name)
+		}
+		if !token.IsIdentifier(f.param) || f.param == "_" || taken(f.param) {
+			log.Panicf("invalid param [%s] for field [%s] in struct [%s]", f.param, f.name, s.name)
+		}
+		if other, ok := used[f.param]; ok {
+			log.Panicf("fields [%s] and [%s] in struct [%s] have the same param %s", other, f.name, s.name, f.param)
+		}
+		params[f.name] = f.param
+		used[f.param] = f.name
+	}
+
+	for _, f := range s.fields {
+		if f.skip || f.gen != "" || f.param != "" {
+			continue
+		}
+
+		name := uniqueParam(f.name, taken, used)
+		params[f.name] = name
+		used[name] = f.name
+	}
+	return params
+}
+
+// uniqueParam returns the lowerCamel name of a param, suffixed with Value when the name is taken then numbered until
+// it is neither taken nor used
+func uniqueParam(name string, taken func(string) bool, used map[string]string) string {
+	base := lowerCamel(name)
+	if taken(base) {
+		base += "Value"
+	}
+	result := base
+	for n := 2; used[result] != "" || taken(result); n++ {
+		result = fmt.Sprintf("%s%d", base, n)
+	}
+	return result
+}
+
+// assignParams assigns the param name of each field, avoiding the imports and the types and functions referenced by the
+// generated code
+func assignParams(structs []genStruct, imports []string) []genStruct {
+	reserved := make(map[string]bool)
+	for _, list := range [][]string{imports, packageImports, testsImports, fuzzImports, exampleImports, factoryImports} {
+		for _, i := range list {
+			reserved[importName(i)] = true
+		}
+	}
+	for _, s := range structs {
+		reserved[s.name] = true
+		for _, f := range s.fields {
+			if f.gen != "" {
+				reserved[f.gen] = true
+			}
+			if f.conv != "" {
+				reserved[strings.Split(f.conv, ".")[0]] = true
+			}
+			for _, n := range f.normalize {
+				if strings.HasPrefix(n, normalizeCall+"=") {
+					reserved[strings.TrimPrefix(n, normalizeCall+"=")] = true
+				}
+			}
+		}
+	}
+
+	result := make([]genStruct, 0, len(structs))
+	for _, s := range structs {
+		params := structParams(s, reserved)
+		fields := make([]genField, len(s.fields))
+		for i, f := range s.fields {
+			if name, ok := params[f.name]; ok {
+				f.param = name
+			}
+			fields[i] = f
+		}
+		s.fields = fields
+		result = append(result, s)
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestImportName(t *testing.T) {
+	assert.Equal(t, "time", importName(`"time"`))
+	assert.Equal(t, "rand", importName(`"math/rand"`))
+	assert.Equal(t, "fmrand", importName(`fmrand "math/rand"`))
+	assert.Equal(t, "errors", importName(`"github.com/pkg/errors"`))
+	assert.Equal(t, "redis", importName(`"github.com/go-redis/redis/v8"`))
+}
+
+func TestLowerCamel(t *testing.T) {
+	assert.Equal(t, "name", lowerCamel("Name"))
+	assert.Equal(t, "id", lowerCamel("ID"))
+	assert.Equal(t, "ids", lowerCamel("IDs"))
+	assert.Equal(t, "httpAddr", lowerCamel("HTTPAddr"))
+	assert.Equal(t, "lastUpdated", lowerCamel("LastUpdated"))
+	assert.Equal(t, "count", lowerCamel("count"))
+}
+
+func TestFlattenedName(t *testing.T) {
+	f := genField{name: "Home", param: "home"}
+	assert.Equal(t, "homeGeoLat", flattenedName(f, genParam{field: genField{name: "Geo.Lat"}}))
+	assert.Equal(t, "homeID", flattenedName(f, genParam{field: genField{name: "ID"}}))
+}
+
+func TestAssignParams(t *testing.T) {
+	structs := []genStruct{{
+		name: "Sample",
+		fields: []genField{
+			{name: "Time", typ: "time.Time"},
+			{name: "Result", typ: "string"},
+			{name: "ID", typ: "string"},
+			{name: "Id", typ: "int"},
+			{name: "Type", typ: "string", param: "kind"},
+			{name: "Len", typ: "int"},
+			{name: "Skipped", typ: "int", skip: true},
+			{name: "Created", typ: "string", gen: "created"},
+			{name: "Sample", typ: "string"},
+		},
+	}}
+	results := assignParams(structs, []string{`"time"`})
+
+	var params []string
+	for _, f := range results[0].fields {
+		params = append(params, f.param)
+	}
+	assert.Equal(t, []string{"timeValue", "resultValue", "id", "id2", "kind", "lenValue", "", "", "sample"}, params)
+	assert.Empty(t, structs[0].fields[0].param)
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Panics(t, func() {
+			assignParams([]genStruct{{name: "Sample", fields: []genField{{name: "Type", typ: "string", param: "type"}}}}, nil)
+		})
+		assert.Panics(t, func() {
+			assignParams([]genStruct{{name: "Sample", fields: []genField{{name: "Kind", typ: "string", param: "a-b"}}}}, nil)
+		})
+		assert.Panics(t, func() {
+			assignParams([]genStruct{{name: "Sample", fields: []genField{
+				{name: "Type", typ: "string", param: "kind"},
+				{name: "Kind", typ: "string", param: "kind"},
+			}}}, nil)
+		})
+		assert.PanicsWithValue(t, "invalid param [string] for field [ID] in struct [Sample], the type of a param can only be changed with conv", func() {
+			assignParams([]genStruct{{name: "Sample", fields: []genField{{name: "ID", typ: "int64", param: "string"}}}}, nil)
+		})
+	})
+}
+
+func TestUniqueParam(t *testing.T) {
+	taken := func(name string) bool { return name == "type" }
+	used := map[string]string{"getFunc": "Get"}
+	assert.Equal(t, "resetFunc", uniqueParam("ResetFunc", taken, used))
+	assert.Equal(t, "getFunc2", uniqueParam("GetFunc", taken, used))
+	assert.Equal(t, "typeValue", uniqueParam("Type", taken, used))
+}
@@ -4,15 +4,27 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 func lineNum(fset *token.FileSet, pos token.Pos) int {
 	return fset.File(pos).Line(pos)
 }
 
+// fileName returns the base name of the file at the position, or an empty string when it was not parsed from a file
+func fileName(fset *token.FileSet, pos token.Pos) string {
+	filename := fset.Position(pos).Filename
+	if filename == "" {
+		return ""
+	}
+	return filepath.Base(filename)
+}
+
 // if the comment is 1 line before the struct definition, then consider it a struct comment
 func findComment(lineNum int, comments []genComment) *genComment {
 	for _, c := range comments {
@@ -51,6 +63,25 @@ func logStructs(w io.Writer, structs []genStruct) {
 	}
 }
 
+func logInterfaces(w io.Writer, interfaces []genInterface) {
+	if len(interfaces) > 0 {
+		fmt.Fprintln(w, "Interfaces")
+		fmt.Fprintln(w, "----------------------------------------------------")
+		for _, i := range interfaces {
+			fmt.Fprintf(w, "Interface:\n")
+			fmt.Fprintf(w, "    name=%s\n", i.name)
+			fmt.Fprintf(w, "    loc=%d\n", i.lineNum)
+			fmt.Fprintf(w, "    Methods:\n")
+			for _, m := range i.methods {
+				fmt.Fprintf(w, "        name=%s params=%d results=%d\n", m.name, len(m.params), len(m.results))
+			}
+			for _, e := range i.embeds {
+				fmt.Fprintf(w, "        embeds=%s\n", e)
+			}
+		}
+	}
+}
+
 func logImports(w io.Writer, imports []string) {
 	if len(imports) > 0 {
 		fmt.Fprintln(w, "Imports")
@@ -74,6 +105,17 @@ func parseImports(node *ast.File) []string {
 	return imports
 }
 
+// parseFieldDoc returns the doc comment of the field, or its line comment when it has no doc comment
+func parseFieldDoc(field *ast.Field) string {
+	if field.Doc != nil {
+		return strings.TrimSpace(field.Doc.Text())
+	}
+	if field.Comment != nil {
+		return strings.TrimSpace(field.Comment.Text())
+	}
+	return ""
+}
+
 func parseFieldName(field *ast.Field) string {
 	return field.Names[0].Name
 }
@@ -84,11 +126,31 @@ func buildField(field *genField, expr ast.Expr, fieldName string, fieldTag *ast.
 		if fieldTag != nil {
 			tags, _ = parseTag(fieldTag.Value)
 		}
+		buffer, init := tags.init()
+		conv, paramType := tags.conv()
 
 		field = &genField{
-			name:     fieldName,
-			optional: tags.optional(),
-			skip:     tags.skip(),
+			name:      fieldName,
+			optional:  tags.optional(),
+			skip:      tags.skip(),
+			seq:       tags.seq(),
+			provider:  tags.provider(),
+			gen:       tags.gen(),
+			normalize: tags.normalizers(),
+			copy:      tags.copy(),
+			init:      init,
+			buffer:    buffer,
+			notNil:    tags.notNil(),
+			allowNil:  tags.allowNil(),
+			oneOf:     tags.oneOf(),
+			anyOf:     tags.anyOf(),
+			requires:  tags.requires(),
+			flatten:   tags.flatten(),
+			ctors:     tags.ctors(),
+			order:     tags.order(),
+			param:     tags.param(),
+			conv:      conv,
+			paramType: paramType,
 		}
 	}
 
@@ -96,14 +158,53 @@ func buildField(field *genField, expr ast.Expr, fieldName string, fieldTag *ast.
 	switch fieldType := expr.(type) {
 	case *ast.Ident:
 		typ = fieldType.Name
+		field.iface = predeclaredInterfaces[typ] && !field.ptr && !field.array
 	case *ast.SelectorExpr:
 		typ = fmt.Sprintf("%s.%s", fieldType.X.(*ast.Ident).Name, fieldType.Sel.Name)
 	case *ast.StarExpr:
 		field.ptr = true
 		return buildField(field, fieldType.X, fieldName, fieldTag)
 	case *ast.ArrayType:
+		if field.array {
+			// nested arrays keep the full type of the element
+			typ = types.ExprString(fieldType)
+			break
+		}
 		field.array = true
 		return buildField(field, fieldType.Elt, fieldName, fieldTag)
+	case *ast.MapType:
+		switch {
+		case field.array:
+			// maps within arrays keep the full type of the element
+		case field.ptr:
+			log.Panicf("skipping field type - %v\n", fieldType)
+		default:
+			field.mapped = true
+		}
+		typ = types.ExprString(fieldType)
+	case *ast.FuncType:
+		switch {
+		case field.array:
+			// funcs within arrays keep the full type of the element
+		case field.ptr:
+			log.Panicf("skipping field type - %v\n", fieldType)
+		default:
+			field.fn = true
+		}
+		typ = types.ExprString(fieldType)
+	case *ast.InterfaceType:
+		field.iface = !field.ptr && !field.array
+		typ = types.ExprString(fieldType)
+	case *ast.ChanType:
+		switch {
+		case field.array:
+			// channels within arrays keep the full type of the element
+		case field.ptr:
+			log.Panicf("skipping field type - %v\n", fieldType)
+		default:
+			field.channel = true
+		}
+		typ = types.ExprString(fieldType)
 	default:
 		log.Panicf("skipping field type - %v\n", fieldType)
 	}
@@ -112,13 +213,13 @@ func buildField(field *genField, expr ast.Expr, fieldName string, fieldTag *ast.
 	return field
 }
 
-func parseStructs(fset *token.FileSet, node *ast.File) []genStruct {
-
-	// process all comments in the file to match with structs later
+// parseComments processes all comments in the file to match with types later, using the last line of multi-line
+// comments
+func parseComments(fset *token.FileSet, node *ast.File) []genComment {
 	var comments []genComment
 	for _, c := range node.Comments {
 		comments = append(comments, genComment{
-			lineNum: lineNum(fset, c.Pos()),
+			lineNum: lineNum(fset, c.End()),
 			value:   c.Text(),
 		})
 	}
@@ -127,6 +228,13 @@ func parseStructs(fset *token.FileSet, node *ast.File) []genStruct {
 		logComments(os.Stdout, comments)
 	}
 
+	return comments
+}
+
+func parseStructs(fset *token.FileSet, node *ast.File) []genStruct {
+
+	comments := parseComments(fset, node)
+
 	var structs []genStruct
 
 	// look for structs and within the file, parse out the fields and tags into a genStruct
@@ -149,14 +257,20 @@ func parseStructs(fset *token.FileSet, node *ast.File) []genStruct {
 						structType := typeSpec.Type.(*ast.StructType)
 						for _, field := range structType.Fields.List {
 							fieldStruct := buildField(nil, field.Type, parseFieldName(field), field.Tag)
+							fieldStruct.doc = parseFieldDoc(field)
 							structFields = append(structFields, *fieldStruct)
 						}
 						structs = append(structs, genStruct{
 							name:    structName,
+							file:    fileName(fset, typeSpec.Pos()),
 							lineNum: structLineNum,
 							fields:  structFields,
 							comment: findComment(structLineNum, comments),
 						})
+					case *ast.InterfaceType:
+						// interfaces are processed by parseInterfaces
+					case *ast.Ident:
+						// defined types are processed by parseTypes
 					default:
 						log.Printf("skipping spec type in [%s], struct [%s] - %v\n", node.Name.Name, structName, typeSpec.Type)
 					}
@@ -171,3 +285,312 @@ func parseStructs(fset *token.FileSet, node *ast.File) []genStruct {
 
 	return structs
 }
+
+// buildArgs converts the parameters or results of a method, unnamed arguments are left with an empty name
+func buildArgs(fields *ast.FieldList) []genArg {
+	var args []genArg
+	if fields == nil {
+		return args
+	}
+
+	for _, field := range fields.List {
+		arg := genArg{typ: types.ExprString(field.Type)}
+		if ellipsis, ok := field.Type.(*ast.Ellipsis); ok {
+			arg.typ = types.ExprString(ellipsis.Elt)
+			arg.variadic = true
+		}
+
+		if len(field.Names) == 0 {
+			args = append(args, arg)
+			continue
+		}
+		for _, name := range field.Names {
+			arg.name = name.Name
+			args = append(args, arg)
+		}
+	}
+	return args
+}
+
+// buildInterface converts the interface type into a genInterface, false is returned when the interface contains type
+// constraints and cannot be implemented
+func buildInterface(name string, ifaceType *ast.InterfaceType) (genInterface, bool) {
+	iface := genInterface{name: name}
+	for _, method := range ifaceType.Methods.List {
+		switch methodType := method.Type.(type) {
+		case *ast.FuncType:
+			iface.methods = append(iface.methods, genMethod{
+				name:    method.Names[0].Name,
+				params:  buildArgs(methodType.Params),
+				results: buildArgs(methodType.Results),
+			})
+		case *ast.Ident, *ast.SelectorExpr:
+			iface.embeds = append(iface.embeds, types.ExprString(methodType))
+		default:
+			return iface, false
+		}
+	}
+	return iface, true
+}
+
+func parseInterfaces(fset *token.FileSet, node *ast.File) []genInterface {
+	comments := parseComments(fset, node)
+
+	var interfaces []genInterface
+
+	// look for interfaces within the file, parse out the methods into a genInterface
+	for _, decl := range node.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			ifaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+
+			ifaceName := typeSpec.Name.Name
+			if typeSpec.TypeParams != nil {
+				log.Printf("skipping generic interface in [%s], interface [%s]\n", node.Name.Name, ifaceName)
+				continue
+			}
+
+			iface, ok := buildInterface(ifaceName, ifaceType)
+			if !ok {
+				log.Printf("skipping constraint interface in [%s], interface [%s]\n", node.Name.Name, ifaceName)
+				continue
+			}
+			iface.lineNum = lineNum(fset, typeSpec.Pos())
+			iface.comment = findComment(iface.lineNum, comments)
+			interfaces = append(interfaces, iface)
+		}
+	}
+
+	if *flagVerbose {
+		logInterfaces(os.Stdout, interfaces)
+	}
+
+	return interfaces
+}
+
+// parseMethods returns the methods declared on named types within the file
+func parseMethods(node *ast.File) []genReceiver {
+	var receivers []genReceiver
+	for _, decl := range node.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+			continue
+		}
+
+		recv := genReceiver{method: funcDecl.Name.Name}
+		expr := funcDecl.Recv.List[0].Type
+		if star, ok := expr.(*ast.StarExpr); ok {
+			recv.ptr = true
+			expr = star.X
+		}
+
+		// methods of generic types are ignored
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		recv.typ = ident.Name
+		receivers = append(receivers, recv)
+	}
+	return receivers
+}
+
+// parseFuncs returns the names of the functions declared within the file, methods are excluded
+func parseFuncs(node *ast.File) []string {
+	var names []string
+	for _, decl := range node.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil {
+			names = append(names, funcDecl.Name.Name)
+		}
+	}
+	return names
+}
+
+// assignMethods sets the methods of each struct from the receivers declared within the package
+func assignMethods(structs []genStruct, receivers []genReceiver) []genStruct {
+	result := make([]genStruct, 0, len(structs))
+	for _, s := range structs {
+		for _, r := range receivers {
+			if r.typ != s.name {
+				continue
+			}
+			if s.methods == nil {
+				s.methods = make(map[string]bool)
+			}
+			s.methods[r.method] = r.ptr
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// assignInterfaces marks the fields of each struct holding an interface declared within the package
+func assignInterfaces(structs []genStruct, interfaces []genInterface) []genStruct {
+	names := make(map[string]bool)
+	for _, i := range interfaces {
+		names[i.name] = true
+	}
+
+	result := make([]genStruct, 0, len(structs))
+	for _, s := range structs {
+		fields := make([]genField, len(s.fields))
+		for i, f := range s.fields {
+			if names[f.typ] && !f.ptr && !f.array {
+				f.iface = true
+			}
+			fields[i] = f
+		}
+		s.fields = fields
+		result = append(result, s)
+	}
+	return result
+}
+
+// basic types which may be the underlying type of a generated defined type
+var definedTypes = map[string]bool{
+	"string": true, "byte": true, "rune": true, "float32": true, "float64": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+}
+
+func parseTypes(fset *token.FileSet, node *ast.File) []genType {
+	comments := parseComments(fset, node)
+
+	var result []genType
+	for _, decl := range node.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+
+			// aliases share the methods of the aliased type
+			ident, ok := typeSpec.Type.(*ast.Ident)
+			if !ok || typeSpec.Assign.IsValid() || !definedTypes[ident.Name] {
+				continue
+			}
+
+			typeLineNum := lineNum(fset, typeSpec.Pos())
+			result = append(result, genType{
+				name:       typeSpec.Name.Name,
+				lineNum:    typeLineNum,
+				underlying: ident.Name,
+				comment:    findComment(typeLineNum, comments),
+			})
+		}
+	}
+	return result
+}
+
+// constType returns the name of the type converted to by an expression such as Status("active")
+func constType(expr ast.Expr) string {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return ""
+	}
+	if ident, ok := call.Fun.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// parseConsts returns the constants declared with a named type, constants without a type or value repeat the type of
+// the previous constant in the block as with iota
+func parseConsts(node *ast.File) []genConst {
+	var result []genConst
+	for _, decl := range node.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+
+		var types []string
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			switch {
+			case valueSpec.Type != nil:
+				typ := ""
+				if ident, ok := valueSpec.Type.(*ast.Ident); ok {
+					typ = ident.Name
+				}
+				types = []string{typ}
+			case len(valueSpec.Values) > 0:
+				types = nil
+				for _, v := range valueSpec.Values {
+					types = append(types, constType(v))
+				}
+			}
+
+			for i, name := range valueSpec.Names {
+				typ := ""
+				if len(types) == 1 {
+					typ = types[0]
+				} else if i < len(types) {
+					typ = types[i]
+				}
+				if typ != "" && name.Name != "_" {
+					result = append(result, genConst{name: name.Name, typ: typ})
+				}
+			}
+		}
+	}
+	return result
+}
+
+// assignConsts sets the constants of each enum from the constants declared within the package, constants of other
+// defined types aren't valid values of their own
+func assignConsts(types []genType, consts []genConst) []genType {
+	result := make([]genType, 0, len(types))
+	for _, t := range types {
+		for _, c := range consts {
+			if !t.enum() {
+				break
+			}
+			if c.typ == t.name {
+				t.consts = append(t.consts, c.name)
+			}
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// assignDeclared sets the names generated for each defined type which are already declared within the package by a
+// function, or by a method of the type
+func assignDeclared(types []genType, funcs []string, receivers []genReceiver) []genType {
+	declared := make(map[string]bool)
+	for _, f := range funcs {
+		declared[f] = true
+	}
+
+	result := make([]genType, 0, len(types))
+	for _, t := range types {
+		if t.generated() {
+			for _, name := range definedFuncs(t) {
+				if declared[name] {
+					t.declared = append(t.declared, name)
+				}
+			}
+			for _, r := range receivers {
+				if r.typ == t.name && r.method == validMethod {
+					t.declared = append(t.declared, t.name+"."+validMethod)
+				}
+			}
+		}
+		result = append(result, t)
+	}
+	return result
+}
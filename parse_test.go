@@ -142,6 +142,7 @@ func TestParseASTFile(t *testing.T) {
 
 		expected1 := genStruct{
 			name:    "Sample",
+			file:    "simple.go",
 			lineNum: 6,
 			fields: []genField{
 				{name: "ID", typ: "int64", optional: false, skip: true},
@@ -165,6 +166,7 @@ func TestParseASTFile(t *testing.T) {
 		assert.Len(t, structs, 1)
 		expected := genStruct{
 			name:    "Pointer",
+			file:    "pointer.go",
 			lineNum: 6,
 			fields: []genField{
 				{name: "ID", typ: "int64", optional: false, skip: true},
@@ -191,6 +193,7 @@ func TestParseASTFile(t *testing.T) {
 		assert.Len(t, structs, 1)
 		expected := genStruct{
 			name:    "Array",
+			file:    "array.go",
 			lineNum: 4,
 			fields: []genField{
 				{name: "String", typ: "string", array: true},
@@ -205,6 +208,36 @@ func TestParseASTFile(t *testing.T) {
 		assert.Equal(t, expected, structs[0])
 	})
 
+	t.Run("multi-line comment", func(t *testing.T) {
+		src := `package testdata
+
+// Sample struct with traits
+// fmgen:trait admin Role="admin"
+type Sample struct {
+	Role string
+	Email string ` + "`fmgen:\"seq=user-%d\"`" + `
+}
+`
+		fset := token.NewFileSet()
+		astFile, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+		assert.NoError(t, err)
+		structs := parseStructs(fset, astFile)
+		assert.Len(t, structs, 1)
+		expected := genStruct{
+			name:    "Sample",
+			lineNum: 5,
+			fields: []genField{
+				{name: "Role", typ: "string"},
+				{name: "Email", typ: "string", seq: "user-%d"},
+			},
+			comment: &genComment{
+				lineNum: 4,
+				value:   "Sample struct with traits\nfmgen:trait admin Role=\"admin\"\n",
+			},
+		}
+		assert.Equal(t, expected, structs[0])
+	})
+
 	t.Run("interface.go", func(t *testing.T) {
 		fset := token.NewFileSet()
 		astFile, err := parser.ParseFile(fset, "testdata/interface.go", nil, parser.ParseComments)
@@ -213,6 +246,7 @@ func TestParseASTFile(t *testing.T) {
 		assert.Len(t, structs, 1)
 		expected := genStruct{
 			name:    "impl",
+			file:    "interface.go",
 			lineNum: 7,
 			fields: []genField{
 				{name: "i", typ: "iface", optional: false, skip: false},
@@ -222,6 +256,240 @@ func TestParseASTFile(t *testing.T) {
 	})
 }
 
+func TestParseInterfaces(t *testing.T) {
+	t.Run("interface.go", func(t *testing.T) {
+		fset := token.NewFileSet()
+		astFile, err := parser.ParseFile(fset, "testdata/interface.go", nil, parser.ParseComments)
+		assert.NoError(t, err)
+		interfaces := parseInterfaces(fset, astFile)
+		assert.Len(t, interfaces, 1)
+		expected := genInterface{
+			name:    "iface",
+			lineNum: 3,
+			methods: []genMethod{
+				{name: "Run"},
+			},
+		}
+		assert.Equal(t, expected, interfaces[0])
+	})
+
+	t.Run("methods", func(t *testing.T) {
+		astData := `package parse
+
+// Store persists values
+type Store interface {
+	Named
+	io.Closer
+	Get(ctx context.Context, key string) (string, error)
+	Put(context.Context, string, ...int) (err error)
+}
+
+type Number interface {
+	~int | ~float64
+}
+
+type List[T any] interface {
+	Get(i int) T
+}
+`
+		fset := token.NewFileSet()
+		parsed, err := parser.ParseFile(fset, "", []byte(astData), parser.ParseComments)
+		assert.NoError(t, err)
+		interfaces := parseInterfaces(fset, parsed)
+		assert.Len(t, interfaces, 1)
+		expected := genInterface{
+			name:    "Store",
+			lineNum: 4,
+			methods: []genMethod{
+				{
+					name:    "Get",
+					params:  []genArg{{name: "ctx", typ: "context.Context"}, {name: "key", typ: "string"}},
+					results: []genArg{{typ: "string"}, {typ: "error"}},
+				},
+				{
+					name:    "Put",
+					params:  []genArg{{typ: "context.Context"}, {typ: "string"}, {typ: "int", variadic: true}},
+					results: []genArg{{name: "err", typ: "error"}},
+				},
+			},
+			embeds: []string{"Named", "io.Closer"},
+			comment: &genComment{
+				lineNum: 3,
+				value:   "Store persists values\n",
+			},
+		}
+		assert.Equal(t, expected, interfaces[0])
+	})
+}
+
+func TestParseMethods(t *testing.T) {
+	astData := `package parse
+
+type Created struct{}
+
+func (Created) isEvent() {}
+
+func (c *Created) Name() string { return "" }
+
+type List[T any] []T
+
+func (l List[T]) Len() int { return len(l) }
+
+func isEvent() {}
+`
+	parsed, err := parser.ParseFile(token.NewFileSet(), "", []byte(astData), parser.ParseComments)
+	assert.NoError(t, err)
+	receivers := parseMethods(parsed)
+	expected := []genReceiver{
+		{typ: "Created", method: "isEvent"},
+		{typ: "Created", method: "Name", ptr: true},
+	}
+	assert.Equal(t, expected, receivers)
+}
+
+func TestAssignMethods(t *testing.T) {
+	structs := []genStruct{{name: "Created"}, {name: "Other"}}
+	receivers := []genReceiver{
+		{typ: "Created", method: "isEvent"},
+		{typ: "Created", method: "Name", ptr: true},
+		{typ: "Status", method: "String"},
+	}
+
+	results := assignMethods(structs, receivers)
+	assert.Equal(t, map[string]bool{"isEvent": false, "Name": true}, results[0].methods)
+	assert.Nil(t, results[1].methods)
+	assert.Nil(t, structs[0].methods)
+}
+
+func TestAssignInterfaces(t *testing.T) {
+	structs := []genStruct{
+		{name: "App", fields: []genField{
+			{name: "Logger", typ: "Logger"},
+			{name: "Loggers", typ: "Logger", array: true},
+			{name: "Name", typ: "string"},
+		}},
+	}
+	result := assignInterfaces(structs, []genInterface{{name: "Logger"}})
+	assert.True(t, result[0].fields[0].iface)
+	assert.False(t, result[0].fields[1].iface)
+	assert.False(t, result[0].fields[2].iface)
+
+	// the parsed structs should not be modified
+	assert.False(t, structs[0].fields[0].iface)
+}
+
+func TestParseTypes(t *testing.T) {
+	astData := `package parse
+
+// Port is a network port
+type Port int
+
+type (
+	Status string
+	Alias  = string
+	Names  []string
+	Sample struct{}
+)
+`
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, "", []byte(astData), parser.ParseComments)
+	assert.NoError(t, err)
+	results := parseTypes(fset, parsed)
+	expected := []genType{
+		{
+			name:       "Port",
+			lineNum:    4,
+			underlying: "int",
+			comment: &genComment{
+				lineNum: 3,
+				value:   "Port is a network port\n",
+			},
+		},
+		{
+			name:       "Status",
+			lineNum:    7,
+			underlying: "string",
+		},
+	}
+	assert.Equal(t, expected, results)
+}
+
+func TestParseConsts(t *testing.T) {
+	astData := `package parse
+
+const (
+	StatusActive   Status = "active"
+	StatusInactive Status = "inactive"
+	Untyped               = "untyped"
+)
+
+const StatusDeleted = Status("deleted")
+
+const (
+	Red Color = iota
+	Green
+	_
+	Blue
+)
+
+const Low, High Level = 1, 10
+`
+	parsed, err := parser.ParseFile(token.NewFileSet(), "", []byte(astData), parser.ParseComments)
+	assert.NoError(t, err)
+	results := parseConsts(parsed)
+	expected := []genConst{
+		{name: "StatusActive", typ: "Status"},
+		{name: "StatusInactive", typ: "Status"},
+		{name: "StatusDeleted", typ: "Status"},
+		{name: "Red", typ: "Color"},
+		{name: "Green", typ: "Color"},
+		{name: "Blue", typ: "Color"},
+		{name: "Low", typ: "Level"},
+		{name: "High", typ: "Level"},
+	}
+	assert.Equal(t, expected, results)
+}
+
+func TestAssignConsts(t *testing.T) {
+	genTypes := []genType{{name: "Status", comment: &genComment{value: "fmgen:enum"}}, {name: "Port"}}
+	consts := []genConst{
+		{name: "StatusActive", typ: "Status"},
+		{name: "Red", typ: "Color"},
+		{name: "StatusInactive", typ: "Status"},
+		{name: "DefaultPort", typ: "Port"},
+	}
+
+	results := assignConsts(genTypes, consts)
+	assert.Equal(t, []string{"StatusActive", "StatusInactive"}, results[0].consts)
+	assert.Nil(t, results[1].consts)
+}
+
+func TestParseFuncs(t *testing.T) {
+	astData := `package parse
+
+func ParseLevel(s string) (Level, error) { return 0, nil }
+
+func (l Level) IsValid() bool { return true }
+`
+	parsed, err := parser.ParseFile(token.NewFileSet(), "", []byte(astData), parser.ParseComments)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ParseLevel"}, parseFuncs(parsed))
+}
+
+func TestAssignDeclared(t *testing.T) {
+	genTypes := []genType{
+		{name: "Level", comment: &genComment{value: "fmgen:enum"}},
+		{name: "Port", comment: &genComment{value: "fmgen:range min=1"}},
+		{name: "ID"},
+	}
+	receivers := []genReceiver{{typ: "Port", method: "IsValid"}, {typ: "ID", method: "IsValid"}}
+
+	results := assignDeclared(genTypes, []string{"ParseLevel", "NewID"}, receivers)
+	assert.Equal(t, []string{"ParseLevel"}, results[0].declared)
+	assert.Equal(t, []string{"Port.IsValid"}, results[1].declared)
+	assert.Nil(t, results[2].declared)
+}
+
 func TestParseImports(t *testing.T) {
 	fset := token.NewFileSet()
 	astFile, err := parser.ParseFile(fset, "testdata/imports.go", nil, parser.ParseComments)
@@ -344,6 +612,93 @@ Name time.Time
 		assert.False(t, result.ptr)
 		assert.False(t, result.array)
 	})
+
+	t.Run("map", func(t *testing.T) {
+		astData := `package parse
+type s struct {
+Name map[string][]int
+}
+`
+		parsed, err := parser.ParseFile(token.NewFileSet(), "", []byte(astData), parser.ParseComments)
+		assert.NoError(t, err)
+
+		field := parsed.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List[0]
+		result := buildField(nil, field.Type, "Name", nil)
+		assert.Equal(t, "map[string][]int", result.typ)
+		assert.True(t, result.mapped)
+		assert.False(t, result.ptr)
+		assert.False(t, result.array)
+	})
+
+	t.Run("channel", func(t *testing.T) {
+		astData := `package parse
+type s struct {
+Name <-chan struct{}
+}
+`
+		parsed, err := parser.ParseFile(token.NewFileSet(), "", []byte(astData), parser.ParseComments)
+		assert.NoError(t, err)
+
+		field := parsed.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List[0]
+		result := buildField(nil, field.Type, "Name", nil)
+		assert.Equal(t, "<-chan struct{}", result.typ)
+		assert.True(t, result.channel)
+		assert.False(t, result.mapped)
+		assert.False(t, result.array)
+	})
+
+	t.Run("func and interfaces", func(t *testing.T) {
+		astData := `package parse
+type s struct {
+Notify func(string) error
+Value interface{}
+Err error
+Errs []error
+}
+`
+		parsed, err := parser.ParseFile(token.NewFileSet(), "", []byte(astData), parser.ParseComments)
+		assert.NoError(t, err)
+
+		fields := parsed.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List
+		result := buildField(nil, fields[0].Type, "Notify", nil)
+		assert.Equal(t, "func(string) error", result.typ)
+		assert.True(t, result.fn)
+		assert.False(t, result.iface)
+
+		result = buildField(nil, fields[1].Type, "Value", nil)
+		assert.Equal(t, "interface{}", result.typ)
+		assert.True(t, result.iface)
+
+		result = buildField(nil, fields[2].Type, "Err", nil)
+		assert.Equal(t, "error", result.typ)
+		assert.True(t, result.iface)
+
+		result = buildField(nil, fields[3].Type, "Errs", nil)
+		assert.False(t, result.iface)
+	})
+
+	t.Run("nested array", func(t *testing.T) {
+		astData := `package parse
+type s struct {
+Name []*[]string
+Attrs []map[string]int
+}
+`
+		parsed, err := parser.ParseFile(token.NewFileSet(), "", []byte(astData), parser.ParseComments)
+		assert.NoError(t, err)
+
+		fields := parsed.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List
+		result := buildField(nil, fields[0].Type, "Name", nil)
+		assert.Equal(t, "[]string", result.typ)
+		assert.True(t, result.ptr)
+		assert.True(t, result.array)
+		assert.Equal(t, "[]*[]string", valueType(*result))
+
+		result = buildField(nil, fields[1].Type, "Attrs", nil)
+		assert.Equal(t, "map[string]int", result.typ)
+		assert.False(t, result.mapped)
+		assert.True(t, result.array)
+	})
 }
 
 func TestWriteImports(t *testing.T) {
@@ -368,3 +723,24 @@ func TestWriteImports(t *testing.T) {
 		assert.Equal(t, expected, buf.String())
 	})
 }
+
+func TestParseFieldDoc(t *testing.T) {
+	astData := `package parse
+
+type Sample struct {
+	// Name of the sample
+	Name string
+	Age  int // age in years
+	ID   string
+}
+`
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, "", []byte(astData), parser.ParseComments)
+	assert.NoError(t, err)
+	structs := parseStructs(fset, parsed)
+	assert.Len(t, structs, 1)
+	assert.Empty(t, structs[0].file)
+	assert.Equal(t, "Name of the sample", structs[0].fields[0].doc)
+	assert.Equal(t, "age in years", structs[0].fields[1].doc)
+	assert.Empty(t, structs[0].fields[2].doc)
+}
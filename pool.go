@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func formatAcquireName(in string) string {
+	return "Acquire" + capitalize(in)
+}
+
+func formatReleaseName(in string) string {
+	return "Release" + capitalize(in)
+}
+
+func formatPoolName(in string) string {
+	return lowerFirst(in) + "Pool"
+}
+
+// buildReset returns the statements resetting every field of the struct, arrays are owned by the pool so they are
+// truncated to keep their capacity and pointers within them are cleared so they can be garbage collected
+func buildReset(s genStruct) string {
+	var sb strings.Builder
+	var kept []string
+	for _, f := range s.fields {
+		if !f.array {
+			continue
+		}
+		if f.ptr {
+			sb.WriteString(fmt.Sprintf("for i := range result.%s {\nresult.%s[i] = nil\n}\n", f.name, f.name))
+		}
+		kept = append(kept, fmt.Sprintf("%s: result.%s[:0],", f.name, f.name))
+	}
+	sb.WriteString(fmt.Sprintf("*result = %s{\n%s}\n", s.name, joinLines(kept)))
+	return sb.String()
+}
+
+func writePool(w io.Writer, s genStruct) {
+	poolName := formatPoolName(s.name)
+	acquireName := formatAcquireName(s.name)
+	releaseName := formatReleaseName(s.name)
+
+	fmt.Fprintf(w, "var %s = sync.Pool{\nNew: func() interface{} {\nreturn new(%s)\n},\n}\n\n", poolName, s.name)
+
+	// acquire uses the same parameters as the factory method, copying arrays into the storage kept by the pool
+	comment := fmt.Sprintf("// %s generated factory method for %s, the result is taken from a pool and should be returned with %s", acquireName, s.name, releaseName)
+	writeFactory(w, acquireName, comment, s, s.policyFields(), poolName)
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "// %s resets every field of the %s and returns it to the pool, it should not be used afterwards\n", releaseName, s.name)
+	fmt.Fprintf(w, "func %s(result *%s) {\n", releaseName, s.name)
+	fmt.Fprintln(w, "if result == nil {\nreturn\n}")
+	fmt.Fprint(w, buildReset(s))
+	fmt.Fprintf(w, "%s.Put(result)\n", poolName)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+}
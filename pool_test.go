@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFormatPoolNames(t *testing.T) {
+	assert.Equal(t, "AcquireSample", formatAcquireName("sample"))
+	assert.Equal(t, "ReleaseSample", formatReleaseName("sample"))
+	assert.Equal(t, "samplePool", formatPoolName("Sample"))
+	assert.Equal(t, "typeValuePool", formatPoolName("Type"))
+}
+
+func TestBuildReset(t *testing.T) {
+	t.Run("no arrays", func(t *testing.T) {
+		s := genStruct{name: "Sample", fields: []genField{{name: "Name", typ: "string"}}}
+		assert.Equal(t, "*result = Sample{\n}\n", buildReset(s))
+	})
+
+	t.Run("arrays", func(t *testing.T) {
+		s := genStruct{
+			name: "Sample",
+			fields: []genField{
+				{name: "Tags", typ: "string", array: true},
+				{name: "Children", typ: "Sample", array: true, ptr: true, optional: true},
+			},
+		}
+		expected := "for i := range result.Children {\nresult.Children[i] = nil\n}\n" +
+			"*result = Sample{\nTags: result.Tags[:0],\nChildren: result.Children[:0],\n}\n"
+		assert.Equal(t, expected, buildReset(s))
+	})
+}
+
+func TestWritePackageFilePool(t *testing.T) {
+	buf := bytes.Buffer{}
+	structs := []genStruct{
+		{
+			name: "Sample",
+			fields: []genField{
+				{name: "ID", typ: "int64", skip: true},
+				{name: "Name", typ: "string"},
+				{name: "Age", typ: "int64", optional: true},
+				{name: "Tags", typ: "string", array: true},
+				{name: "Parent", typ: "Sample", ptr: true},
+				{name: "Children", typ: "Sample", array: true, ptr: true, optional: true},
+			},
+			comment: &genComment{value: "fmgen:pool"},
+		},
+	}
+
+	expected := `// Code generated by "fmgen". DO NOT EDIT.
+package testdata
+
+import (
+	"sync"
+)
+
+// NewSample generated factory method for Sample
+//
+// Parameters:
+//   - Name
+//   - Tags
+//   - Parent
+//   - Age: optional, nil leaves Age unset
+//   - Children: optional, nil leaves Children unset
+func NewSample(Name string, Tags []string, Parent Sample, Age *int64, Children []*Sample) *Sample {
+	result := &Sample{
+		Name:   Name,
+		Tags:   Tags,
+		Parent: &Parent,
+	}
+	if Age != nil {
+		result.Age = *Age
+	}
+	if Children != nil {
+		result.Children = Children
+	}
+	return result
+}
+
+var samplePool = sync.Pool{
+	New: func() interface{} {
+		return new(Sample)
+	},
+}
+
+// AcquireSample generated factory method for Sample, the result is taken from a pool and should be returned with ReleaseSample
+//
+// Parameters:
+//   - Name
+//   - Tags
+//   - Parent
+//   - Age: optional, nil leaves Age unset
+//   - Children: optional, nil leaves Children unset
+func AcquireSample(Name string, Tags []string, Parent Sample, Age *int64, Children []*Sample) *Sample {
+	result := samplePool.Get().(*Sample)
+	result.Name = Name
+	result.Tags = append(result.Tags[:0], Tags...)
+	result.Parent = &Parent
+	if Age != nil {
+		result.Age = *Age
+	}
+	if Children != nil {
+		result.Children = append(result.Children[:0], Children...)
+	}
+	return result
+}
+
+// ReleaseSample resets every field of the Sample and returns it to the pool, it should not be used afterwards
+func ReleaseSample(result *Sample) {
+	if result == nil {
+		return
+	}
+	for i := range result.Children {
+		result.Children[i] = nil
+	}
+	*result = Sample{
+		Tags:     result.Tags[:0],
+		Children: result.Children[:0],
+	}
+	samplePool.Put(result)
+}
+`
+
+	writePackageFile(&buf, "testdata", []string{}, structs, nil, nil)
+	assert.Equal(t, expected, buf.String())
+}
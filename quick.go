@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+)
+
+var quickImports = []string{`"fmt"`, `fmrand "math/rand"`, `"reflect"`, `"time"`}
+
+// unquickable returns the reason generated values can't be valid inputs to the factory method of the struct, true is
+// returned when its generator should be skipped
+func unquickable(s genStruct, structs map[string]genStruct) (string, bool) {
+	if name, ok := uncreatableParam(s); ok {
+		return fmt.Sprintf("a non-nil [%s] can't be created", name), true
+	}
+	if reason, ok := constraintConflict(s.fields); ok {
+		return reason, true
+	}
+	for _, f := range constrainedFields(s.fields) {
+		if buildQuickField(s, f, structs) == "" {
+			return fmt.Sprintf("a value for constrained [%s] can't be generated", f.name), true
+		}
+	}
+	return "", false
+}
+
+// buildQuickField returns the statements assigning a random value to the field within a quick.Generator, or an empty
+// string when the field should be left as the zero value
+func buildQuickField(s genStruct, f genField, structs map[string]genStruct) string {
+	if f.skip {
+		return ""
+	}
+	constrained := isConstrained(s.fields, f.name)
+
+	// checked and constrained fields must not be nil, arrays are created empty before any values are appended
+	var created string
+	if v, ok := nilCheckedValue(f); ok && (nilChecked(f) || constrained) {
+		created = fmt.Sprintf("result.%s = %s\n", f.name, v)
+	}
+
+	assign := created
+	if nested, ok := structs[f.typ]; ok {
+		// recursive fields are checked first, as checking whether the nested struct has a generator follows its fields
+		if isRecursiveField(s, f, structs) {
+			return ""
+		}
+		if _, skipped := unquickable(nested, structs); skipped {
+			return ""
+		}
+		value := fmt.Sprintf("%s{}.Generate(r, size).Interface().(%s)", f.typ, f.typ)
+		assign += assignRandom(f, "result."+f.name, value, false, "r.Intn(size + 1)")
+	} else if value, ok := randomValue(f, "r"); ok {
+		assign += assignRandom(f, "result."+f.name, value, false, "r.Intn(size + 1)")
+	} else if constrained && f.ptr && !f.array {
+		// constrained pointers are set to the zero value when a random one can't be created
+		assign = fmt.Sprintf("result.%s = new(%s)\n", f.name, f.typ)
+	}
+	if assign == "" {
+		return ""
+	}
+
+	// optional fields may be nil when passed to the factory, leaving them as the zero value, while constrained fields
+	// are only set when chosen to satisfy the constraints
+	switch {
+	case constrained:
+		return fmt.Sprintf("if %s {\n%s}\n", formatSetName(f.name), assign)
+	case f.optional:
+		return fmt.Sprintf("if r.Intn(2) == 1 {\n%s}\n", assign)
+	}
+	return assign
+}
+
+func writeQuickGenerator(w io.Writer, s genStruct, structs map[string]genStruct) {
+	fmt.Fprintf(w, "// Generate implements quick.Generator for %s, skipped fields are left as the zero value\n", s.name)
+	fmt.Fprintf(w, "func (%s) Generate(r *fmrand.Rand, size int) reflect.Value {\n", s.name)
+	fmt.Fprintf(w, "result := %s{}\n", s.name)
+	fmt.Fprint(w, buildQuickConstraints(s.fields))
+	for _, f := range s.fields {
+		fmt.Fprint(w, buildQuickField(s, f, structs))
+	}
+	fmt.Fprintln(w, "return reflect.ValueOf(result)")
+	fmt.Fprintln(w, "}")
+}
+
+func writeQuickFile(w io.Writer, pkg string, pkgImports []string, structs []genStruct) {
+	log.Printf("generating quick.Generator file for package [%s]", pkg)
+
+	byName := structsByName(structs)
+	writeGoFile(w, quickFileName, pkg, mergeImports(pkgImports, quickImports...), func(buf io.Writer) {
+		for _, s := range structs {
+			if reason, ok := unquickable(s, byName); ok {
+				log.Printf("skipping quick.Generator for struct [%s], %s\n", s.name, reason)
+				continue
+			}
+			writeQuickGenerator(buf, s, byName)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuildQuickField(t *testing.T) {
+	s := genStruct{name: "Sample"}
+	structs := structsByName([]genStruct{
+		s,
+		{name: "Address", fields: []genField{{name: "City", typ: "string"}}},
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		assert.Empty(t, buildQuickField(s, genField{name: "ID", typ: "int64", skip: true}, structs))
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		assert.Empty(t, buildQuickField(s, genField{name: "BaseURL", typ: "url.URL"}, structs))
+	})
+
+	t.Run("recursive struct", func(t *testing.T) {
+		assert.Empty(t, buildQuickField(s, genField{name: "Parent", typ: "Sample", ptr: true}, structs))
+	})
+
+	t.Run("nested struct", func(t *testing.T) {
+		result := buildQuickField(s, genField{name: "Home", typ: "Address"}, structs)
+		assert.Equal(t, "result.Home = Address{}.Generate(r, size).Interface().(Address)\n", result)
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		result := buildQuickField(s, genField{name: "Age", typ: "int64", optional: true}, structs)
+		assert.Equal(t, "if r.Intn(2) == 1 {\nresult.Age = int64(18 + r.Intn(60))\n}\n", result)
+	})
+
+	t.Run("nil checked", func(t *testing.T) {
+		result := buildQuickField(s, genField{name: "Attrs", typ: "map[string]int", mapped: true}, structs)
+		assert.Equal(t, "result.Attrs = make(map[string]int)\n", result)
+
+		result = buildQuickField(s, genField{name: "Tags", typ: "string", array: true, notNil: true}, structs)
+		expected := `result.Tags = []string{}
+for i, n := 0, r.Intn(size + 1); i < n; i++ {
+result.Tags = append(result.Tags, fmt.Sprintf("tags-%d", r.Intn(10000)))
+}
+`
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("unquickable struct", func(t *testing.T) {
+		structs := structsByName([]genStruct{{name: "Handler", fields: []genField{{name: "Fn", typ: "func()", fn: true}}}})
+		assert.Empty(t, buildQuickField(s, genField{name: "Handler", typ: "Handler"}, structs))
+	})
+
+	t.Run("constrained", func(t *testing.T) {
+		s := genStruct{name: "Config", fields: []genField{
+			{name: "File", typ: "string", ptr: true, optional: true, oneOf: "source"},
+			{name: "Inline", typ: "byte", array: true, optional: true, oneOf: "source"},
+		}}
+		result := buildQuickField(s, s.fields[0], structs)
+		assert.Equal(t, "if setFile {\n{\nv := fmt.Sprintf(\"file-%d\", r.Intn(10000))\nresult.File = &v\n}\n}\n", result)
+
+		// set arrays are never nil
+		result = buildQuickField(s, s.fields[1], structs)
+		expected := `if setInline {
+result.Inline = []byte{}
+for i, n := 0, r.Intn(size + 1); i < n; i++ {
+result.Inline = append(result.Inline, byte(r.Intn(100)))
+}
+}
+`
+		assert.Equal(t, expected, result)
+
+		// pointers are set to the zero value when a random one can't be created
+		s.fields = append(s.fields, genField{name: "BaseURL", typ: "url.URL", ptr: true, optional: true, oneOf: "source"})
+		result = buildQuickField(s, s.fields[2], structs)
+		assert.Equal(t, "if setBaseURL {\nresult.BaseURL = new(url.URL)\n}\n", result)
+	})
+
+	t.Run("array sized", func(t *testing.T) {
+		result := buildQuickField(s, genField{name: "Active", typ: "bool", array: true}, structs)
+		expected := `for i, n := 0, r.Intn(size + 1); i < n; i++ {
+result.Active = append(result.Active, r.Intn(2) == 1)
+}
+`
+		assert.Equal(t, expected, result)
+	})
+}
+
+func TestUnquickable(t *testing.T) {
+	_, ok := unquickable(genStruct{name: "Sample", fields: []genField{{name: "Attrs", typ: "map[string]int", mapped: true}}}, nil)
+	assert.False(t, ok)
+
+	reason, ok := unquickable(genStruct{name: "Sample", fields: []genField{{name: "Logger", typ: "Logger", iface: true}}}, nil)
+	assert.True(t, ok)
+	assert.Equal(t, "a non-nil [Logger] can't be created", reason)
+
+	_, ok = unquickable(genStruct{name: "Config", fields: []genField{
+		{name: "File", typ: "string", ptr: true, optional: true, oneOf: "source"},
+		{name: "URL", typ: "string", ptr: true, optional: true, oneOf: "source"},
+	}}, nil)
+	assert.False(t, ok)
+
+	reason, ok = unquickable(genStruct{name: "Config", fields: []genField{
+		{name: "File", typ: "string", ptr: true, optional: true, oneOf: "source"},
+		{name: "BaseURL", typ: "url.URL", optional: true, oneOf: "source"},
+	}}, nil)
+	assert.True(t, ok)
+	assert.Equal(t, "a value for constrained [BaseURL] can't be generated", reason)
+
+	reason, ok = unquickable(genStruct{name: "Config", fields: []genField{
+		{name: "File", typ: "string", ptr: true, optional: true, oneOf: "source"},
+		{name: "URL", typ: "string", ptr: true, optional: true, oneOf: "source"},
+		{name: "Token", typ: "string", ptr: true, optional: true, requires: []string{"URL"}},
+	}}, nil)
+	assert.True(t, ok)
+	assert.Equal(t, "[URL] in oneof group [source] is required by [Token]", reason)
+}
+
+func TestWriteQuickFile(t *testing.T) {
+	buf := bytes.Buffer{}
+	structs := []genStruct{
+		{
+			name: "Sample",
+			fields: []genField{
+				{name: "ID", typ: "int64", skip: true},
+				{name: "Name", typ: "string"},
+				{name: "LastUpdated", typ: "time.Time", ptr: true},
+			},
+		},
+		{
+			name: "Config",
+			fields: []genField{
+				{name: "File", typ: "string", ptr: true, optional: true, oneOf: "source"},
+				{name: "URL", typ: "string", ptr: true, optional: true, oneOf: "source"},
+				{name: "Username", typ: "string", ptr: true, optional: true, requires: []string{"Password"}},
+				{name: "Password", typ: "string", ptr: true, optional: true},
+			},
+		},
+	}
+
+	expected := `// Code generated by "fmgen". DO NOT EDIT.
+package testdata
+
+import (
+	"fmt"
+	fmrand "math/rand"
+	"reflect"
+	"time"
+)
+
+// Generate implements quick.Generator for Sample, skipped fields are left as the zero value
+func (Sample) Generate(r *fmrand.Rand, size int) reflect.Value {
+	result := Sample{}
+	result.Name = []string{"Alice", "Bob", "Carol", "Dave", "Erin", "Frank"}[r.Intn(6)]
+	{
+		v := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(r.Int63n(int64(365 * 24 * time.Hour))))
+		result.LastUpdated = &v
+	}
+	return reflect.ValueOf(result)
+}
+
+// Generate implements quick.Generator for Config, skipped fields are left as the zero value
+func (Config) Generate(r *fmrand.Rand, size int) reflect.Value {
+	result := Config{}
+	setFile := false
+	setURL := false
+	setUsername := r.Intn(2) == 1
+	setPassword := r.Intn(2) == 1
+	switch r.Intn(2) {
+	case 0:
+		setFile = true
+	case 1:
+		setURL = true
+	}
+	if setUsername {
+		setPassword = true
+	}
+	if setFile {
+		{
+			v := fmt.Sprintf("file-%d", r.Intn(10000))
+			result.File = &v
+		}
+	}
+	if setURL {
+		{
+			v := fmt.Sprintf("https://example.com/%d", r.Intn(10000))
+			result.URL = &v
+		}
+	}
+	if setUsername {
+		{
+			v := []string{"Alice", "Bob", "Carol", "Dave", "Erin", "Frank"}[r.Intn(6)]
+			result.Username = &v
+		}
+	}
+	if setPassword {
+		{
+			v := fmt.Sprintf("password-%d", r.Intn(10000))
+			result.Password = &v
+		}
+	}
+	return reflect.ValueOf(result)
+}
+`
+
+	// the package importing crypto/rand doesn't collide with math/rand
+	writeQuickFile(&buf, "testdata", []string{`"crypto/rand"`, `"time"`}, structs)
+	assert.Equal(t, expected, buf.String())
+}
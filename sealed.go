@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+)
+
+func formatSealedName(iface string, s genStruct) string {
+	return s.factoryName() + "As" + capitalize(iface)
+}
+
+func formatVisitName(in string) string {
+	return "Visit" + capitalize(in)
+}
+
+func formatVariantsName(in string) string {
+	return capitalize(in) + "Variants"
+}
+
+// implements returns true when the struct declares every method of the interface, and whether the struct value
+// implements the interface without a pointer receiver
+func implements(s genStruct, methods []genMethod) (bool, bool) {
+	value := true
+	for _, m := range methods {
+		ptr, ok := s.methods[m.name]
+		if !ok {
+			return false, false
+		}
+		value = value && !ptr
+	}
+	return true, value
+}
+
+// sealedVariants returns the structs implementing the sealed interface sorted by name, so the generated code doesn't
+// depend on the order files are parsed in
+func sealedVariants(methods []genMethod, structs []genStruct) []genStruct {
+	var variants []genStruct
+	for _, s := range structs {
+		if ok, _ := implements(s, methods); ok {
+			variants = append(variants, s)
+		}
+	}
+	sort.SliceStable(variants, func(i, j int) bool {
+		return variants[i].name < variants[j].name
+	})
+	return variants
+}
+
+func writeSealedConstructor(w io.Writer, iface genInterface, s genStruct) {
+	var args []string
+	for _, p := range factoryParams(s.fields) {
+		args = append(args, p.name)
+	}
+
+	call := fmt.Sprintf("%s(%s)", s.factoryName(), strings.Join(args, ", "))
+	sealedName := formatSealedName(iface.name, s)
+	fmt.Fprintf(w, "// %s generated factory method for %s as the sealed interface %s\n", sealedName, s.name, iface.name)
+	if returnsError(s.fields) {
+		// a nil struct is returned as a nil interface along with the error
+		fmt.Fprintf(w, "func %s(%s) (%s, error) {\n", sealedName, buildInputParams(s.fields), iface.name)
+		fmt.Fprint(w, buildCall(s.fields, "result", call, "return nil, err\n"))
+		fmt.Fprintln(w, "return result, nil")
+	} else {
+		fmt.Fprintf(w, "func %s(%s) %s {\n", sealedName, buildInputParams(s.fields), iface.name)
+		fmt.Fprintf(w, "return %s\n", call)
+	}
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+}
+
+func writeSealed(w io.Writer, iface genInterface, interfaces map[string]genInterface, structs []genStruct) {
+	methods, ok := resolveMethods(iface, interfaces, make(map[string]bool))
+	if !ok {
+		log.Panicf("unable to generate sealed interface %s, embedded interfaces could not be resolved", iface.name)
+	}
+	if len(methods) == 0 {
+		log.Panicf("unable to generate sealed interface %s, no methods are declared", iface.name)
+	}
+
+	variants := sealedVariants(methods, structs)
+	if len(variants) == 0 {
+		log.Printf("skipping sealed interface [%s], no structs implement it\n", iface.name)
+		return
+	}
+
+	// constructors are only generated for variants with a factory method
+	for _, s := range variants {
+		if !s.Skip() {
+			writeSealedConstructor(w, iface, s)
+		}
+	}
+
+	var values, callbacks, cases []string
+	for _, s := range variants {
+		callback := "on" + capitalize(s.name)
+		values = append(values, fmt.Sprintf("(*%s)(nil)", s.name))
+		callbacks = append(callbacks, fmt.Sprintf("%s func(*%s)", callback, s.name))
+		cases = append(cases, fmt.Sprintf("case *%s:\n%s(variant)", s.name, callback))
+		if _, value := implements(s, methods); value {
+			cases = append(cases, fmt.Sprintf("case %s:\n%s(&variant)", s.name, callback))
+		}
+	}
+
+	variantsName := formatVariantsName(iface.name)
+	fmt.Fprintf(w, "// %s lists every variant of the sealed interface %s\n", variantsName, iface.name)
+	fmt.Fprintf(w, "var %s = []%s{%s}\n\n", variantsName, iface.name, strings.Join(values, ", "))
+
+	visitName := formatVisitName(iface.name)
+	fmt.Fprintf(w, "// %s calls the callback matching the variant of %s, panics when the variant is unknown\n", visitName, iface.name)
+	fmt.Fprintf(w, "func %s(v %s, %s) {\n", visitName, iface.name, strings.Join(callbacks, ", "))
+	fmt.Fprintln(w, "switch variant := v.(type) {")
+	fmt.Fprint(w, joinLines(cases))
+	fmt.Fprintln(w, "default:")
+	fmt.Fprintf(w, "panic(fmt.Sprintf(\"unknown %s variant %%T\", v))\n", iface.name)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFormatSealedName(t *testing.T) {
+	assert.Equal(t, "NewCreatedAsEvent", formatSealedName("Event", genStruct{name: "Created"}))
+	assert.Equal(t, "NewCreatedAsEvent", formatSealedName("event", genStruct{name: "created"}))
+	assert.Equal(t, "VisitEvent", formatVisitName("event"))
+	assert.Equal(t, "EventVariants", formatVariantsName("event"))
+}
+
+func TestImplements(t *testing.T) {
+	methods := []genMethod{{name: "isEvent"}, {name: "Name"}}
+
+	ok, value := implements(genStruct{name: "Created", methods: map[string]bool{"isEvent": false, "Name": false}}, methods)
+	assert.True(t, ok)
+	assert.True(t, value)
+
+	ok, value = implements(genStruct{name: "Deleted", methods: map[string]bool{"isEvent": true, "Name": false}}, methods)
+	assert.True(t, ok)
+	assert.False(t, value)
+
+	ok, _ = implements(genStruct{name: "Other", methods: map[string]bool{"isEvent": false}}, methods)
+	assert.False(t, ok)
+
+	ok, _ = implements(genStruct{name: "Empty"}, methods)
+	assert.False(t, ok)
+}
+
+func TestSealedVariants(t *testing.T) {
+	methods := []genMethod{{name: "isEvent"}}
+	structs := []genStruct{
+		{name: "Updated", methods: map[string]bool{"isEvent": false}},
+		{name: "Other"},
+		{name: "Created", methods: map[string]bool{"isEvent": true}},
+	}
+
+	variants := sealedVariants(methods, structs)
+	assert.Len(t, variants, 2)
+	assert.Equal(t, "Created", variants[0].name)
+	assert.Equal(t, "Updated", variants[1].name)
+}
+
+func TestWriteSealed(t *testing.T) {
+	t.Run("no methods", func(t *testing.T) {
+		iface := genInterface{name: "Event"}
+		assert.Panics(t, func() {
+			writeSealed(&bytes.Buffer{}, iface, interfacesByName([]genInterface{iface}), nil)
+		})
+	})
+
+	t.Run("unresolved embedded interface", func(t *testing.T) {
+		iface := genInterface{name: "Event", embeds: []string{"fmt.Stringer"}}
+		assert.Panics(t, func() {
+			writeSealed(&bytes.Buffer{}, iface, interfacesByName([]genInterface{iface}), nil)
+		})
+	})
+
+	t.Run("no variants", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		iface := genInterface{name: "Event", methods: []genMethod{{name: "isEvent"}}}
+		writeSealed(&buf, iface, interfacesByName([]genInterface{iface}), nil)
+		assert.Empty(t, buf.String())
+	})
+}
+
+func TestWriteSealedConstructor(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		s := genStruct{name: "Circle", fields: []genField{{name: "Attrs", typ: "map[string]string", mapped: true}}}
+		writeSealedConstructor(&buf, genInterface{name: "Shape"}, s)
+
+		expected := `// NewCircleAsShape generated factory method for Circle as the sealed interface Shape
+func NewCircleAsShape(Attrs map[string]string) (Shape, error) {
+result, err := NewCircle(Attrs)
+if err != nil {
+return nil, err
+}
+return result, nil
+}
+
+`
+		assert.Equal(t, expected, buf.String())
+	})
+}
+
+func TestWritePackageFileSealed(t *testing.T) {
+	buf := bytes.Buffer{}
+	structs := []genStruct{
+		{
+			name: "Deleted",
+			fields: []genField{
+				{name: "ID", typ: "string"},
+			},
+			methods: map[string]bool{"isEvent": true},
+		},
+		{
+			name: "Created",
+			fields: []genField{
+				{name: "ID", typ: "string"},
+				{name: "Note", typ: "string", optional: true},
+			},
+			methods: map[string]bool{"isEvent": false},
+		},
+		{
+			name:    "Hidden",
+			comment: &genComment{value: "fmgen:-"},
+			methods: map[string]bool{"isEvent": true},
+		},
+	}
+	interfaces := []genInterface{
+		{
+			name:    "Event",
+			methods: []genMethod{{name: "isEvent"}},
+			comment: &genComment{value: "fmgen:sealed"},
+		},
+		{
+			name:    "iface",
+			methods: []genMethod{{name: "isEvent"}},
+		},
+	}
+
+	expected := `// Code generated by "fmgen". DO NOT EDIT.
+package testdata
+
+import (
+	"fmt"
+)
+
+// NewDeleted generated factory method for Deleted
+//
+// Parameters:
+//   - ID
+func NewDeleted(ID string) *Deleted {
+	result := &Deleted{
+		ID: ID,
+	}
+	return result
+}
+
+// NewCreated generated factory method for Created
+//
+// Parameters:
+//   - ID
+//   - Note: optional, nil leaves Note unset
+func NewCreated(ID string, Note *string) *Created {
+	result := &Created{
+		ID: ID,
+	}
+	if Note != nil {
+		result.Note = *Note
+	}
+	return result
+}
+
+// NewCreatedAsEvent generated factory method for Created as the sealed interface Event
+func NewCreatedAsEvent(ID string, Note *string) Event {
+	return NewCreated(ID, Note)
+}
+
+// NewDeletedAsEvent generated factory method for Deleted as the sealed interface Event
+func NewDeletedAsEvent(ID string) Event {
+	return NewDeleted(ID)
+}
+
+// EventVariants lists every variant of the sealed interface Event
+var EventVariants = []Event{(*Created)(nil), (*Deleted)(nil), (*Hidden)(nil)}
+
+// VisitEvent calls the callback matching the variant of Event, panics when the variant is unknown
+func VisitEvent(v Event, onCreated func(*Created), onDeleted func(*Deleted), onHidden func(*Hidden)) {
+	switch variant := v.(type) {
+	case *Created:
+		onCreated(variant)
+	case Created:
+		onCreated(&variant)
+	case *Deleted:
+		onDeleted(variant)
+	case *Hidden:
+		onHidden(variant)
+	default:
+		panic(fmt.Sprintf("unknown Event variant %T", v))
+	}
+}
+`
+
+	writePackageFile(&buf, "testdata", []string{}, structs, interfaces, nil)
+	assert.Equal(t, expected, buf.String())
+}
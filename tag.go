@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"go/types"
 	"regexp"
 	"strings"
 )
@@ -9,10 +10,29 @@ import (
 const (
 	tagSkip     = "-"
 	tagOptional = "optional"
+	tagSeq      = "seq"
+	tagNow      = "now"
+	tagID       = "id"
+	tagProvider = "provider"
+	tagGen      = "gen"
+	tagCopy     = "copy"
+	tagInit     = "init"
+	tagNotNil   = "notnil"
+	tagAllowNil = "allownil"
+	tagOneOf    = "oneof"
+	tagAnyOf    = "anyof"
+	tagRequires = "requires"
+	tagFlatten  = "flatten"
+	tagFactory  = "factory"
+	tagCtor     = "ctor"
+	tagOrder    = "order"
+	tagParam    = "param"
+	tagConv     = "conv"
+	tagConvType = "paramtype"
 	tagName     = "fmgen"
 )
 
-var tagRegex = regexp.MustCompile(fmt.Sprintf(`.*%s:"([^\)]+)".*`, tagName))
+var tagRegex = regexp.MustCompile(fmt.Sprintf(`.*%s:"([^"]+)".*`, tagName))
 
 type tag struct {
 	values []string
@@ -36,6 +56,181 @@ func (t tag) optional() bool {
 	return false
 }
 
+// seq returns the format of a sequence tag, defaulting to %d when no format is provided
+func (t tag) seq() string {
+	format, ok := t.value(tagSeq)
+	if !ok {
+		return ""
+	}
+	if format == "" {
+		return "%d"
+	}
+	return format
+}
+
+// provider returns the name of the factory provider filling the field, fmgen:"now" and fmgen:"id" are shorthand for
+// the Now and NewID providers
+func (t tag) provider() string {
+	if name, ok := t.value(tagProvider); ok {
+		return name
+	}
+	if _, ok := t.value(tagNow); ok {
+		return "Now"
+	}
+	if _, ok := t.value(tagID); ok {
+		return "NewID"
+	}
+	return ""
+}
+
+// gen returns the function called to compute the field, fmgen:"now" is shorthand for time.Now
+func (t tag) gen() string {
+	if name, ok := t.value(tagGen); ok {
+		return name
+	}
+	if _, ok := t.value(tagNow); ok {
+		return "time.Now"
+	}
+	return ""
+}
+
+func (t tag) copy() bool {
+	_, ok := t.value(tagCopy)
+	return ok
+}
+
+// init returns the channel buffer size of fmgen:"init=N", true is returned when a nil input should be initialized
+func (t tag) init() (string, bool) {
+	buffer, ok := t.value(tagInit)
+	return buffer, ok
+}
+
+func (t tag) notNil() bool {
+	_, ok := t.value(tagNotNil)
+	return ok
+}
+
+func (t tag) allowNil() bool {
+	_, ok := t.value(tagAllowNil)
+	return ok
+}
+
+// oneOf returns the group of fields where exactly one must be set
+func (t tag) oneOf() string {
+	group, _ := t.value(tagOneOf)
+	return group
+}
+
+// anyOf returns the group of fields where at least one must be set
+func (t tag) anyOf() string {
+	group, _ := t.value(tagAnyOf)
+	return group
+}
+
+// requires returns the fields which must be set when the field is set, the tag may be repeated
+func (t tag) requires() []string {
+	var result []string
+	for _, v := range t.values {
+		if strings.HasPrefix(v, tagRequires+"=") {
+			result = append(result, strings.TrimPrefix(v, tagRequires+"="))
+		}
+	}
+	return result
+}
+
+// ctors returns the named constructors the field is required in, e.g. fmgen:"ctor=Minimal|Full"
+func (t tag) ctors() []string {
+	value, ok := t.value(tagCtor)
+	if !ok || value == "" {
+		return nil
+	}
+	return strings.Split(value, "|")
+}
+
+// order returns the position of the param declared with fmgen:"order=N"
+func (t tag) order() string {
+	n, _ := t.value(tagOrder)
+	return n
+}
+
+// isBuiltinType returns true when the name is a predeclared type such as string or int64
+func isBuiltinType(name string) bool {
+	_, ok := types.Universe.Lookup(name).(*types.TypeName)
+	return ok
+}
+
+// paramIsType returns true when fmgen:"param=T" declares the type of a converted param rather than its name, e.g.
+// fmgen:"param=string,conv=uuid.Parse", as a predeclared type can't be used as a param name
+func (t tag) paramIsType() bool {
+	name, _ := t.value(tagParam)
+	conv, _ := t.value(tagConv)
+	return conv != "" && isBuiltinType(name)
+}
+
+// param returns the name of the input param declared with fmgen:"param=name"
+func (t tag) param() string {
+	if t.paramIsType() {
+		return ""
+	}
+	name, _ := t.value(tagParam)
+	return name
+}
+
+// conv returns the function converting the param to the type of the field along with the type of the param, which
+// defaults to string, e.g. fmgen:"conv=time.ParseDuration,paramtype=string" or fmgen:"param=string,conv=uuid.Parse"
+func (t tag) conv() (string, string) {
+	conv, _ := t.value(tagConv)
+	if conv == "" {
+		return "", ""
+	}
+	if paramType, _ := t.value(tagConvType); paramType != "" {
+		return conv, paramType
+	}
+	if t.paramIsType() {
+		paramType, _ := t.value(tagParam)
+		return conv, paramType
+	}
+	return conv, "string"
+}
+
+// flatten returns fmgen:"flatten" when the required fields of a nested struct are passed in, or fmgen:"factory" when
+// all of the parameters of its factory method are passed in
+func (t tag) flatten() string {
+	for _, key := range []string{tagFlatten, tagFactory} {
+		if _, ok := t.value(key); ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// normalizers returns the tags normalizing the input before it is assigned, in the order they are declared
+func (t tag) normalizers() []string {
+	var result []string
+	for _, v := range t.values {
+		key := strings.SplitN(v, "=", 2)[0]
+		for _, n := range normalizeTags {
+			if key == n {
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
+
+// value returns the value of a key=value tag, a tag with only the key will return an empty value
+func (t tag) value(key string) (string, bool) {
+	for _, v := range t.values {
+		if v == key {
+			return "", true
+		}
+		if strings.HasPrefix(v, key+"=") {
+			return strings.TrimPrefix(v, key+"="), true
+		}
+	}
+	return "", false
+}
+
 func parseTag(allTags string) (tag, bool) {
 	rs := tagRegex.FindStringSubmatch(allTags)
 	if len(rs) <= 1 {
@@ -23,4 +23,187 @@ func TestParseTag(t *testing.T) {
 		assert.True(t, results.skip())
 		assert.True(t, results.optional())
 	})
+	t.Run("fmgen tag before other tags", func(t *testing.T) {
+		results, found := parseTag(`fmgen:"seq=user-%d" json:"email"`)
+		assert.True(t, found)
+		assert.Equal(t, []string{"seq=user-%d"}, results.values)
+	})
+}
+
+func TestTagValue(t *testing.T) {
+	results, _ := parseTag(`fmgen:"optional,seq=user-%d"`)
+
+	value, found := results.value("seq")
+	assert.True(t, found)
+	assert.Equal(t, "user-%d", value)
+
+	value, found = results.value("optional")
+	assert.True(t, found)
+	assert.Empty(t, value)
+
+	_, found = results.value("opt")
+	assert.False(t, found)
+}
+
+func TestTagSeq(t *testing.T) {
+	results, _ := parseTag(`fmgen:"seq=user-%d"`)
+	assert.Equal(t, "user-%d", results.seq())
+
+	results, _ = parseTag(`fmgen:"seq"`)
+	assert.Equal(t, "%d", results.seq())
+
+	results, _ = parseTag(`fmgen:"optional"`)
+	assert.Empty(t, results.seq())
+}
+
+func TestTagProvider(t *testing.T) {
+	results, _ := parseTag(`fmgen:"now"`)
+	assert.Equal(t, "Now", results.provider())
+
+	results, _ = parseTag(`fmgen:"id"`)
+	assert.Equal(t, "NewID", results.provider())
+
+	results, _ = parseTag(`fmgen:"optional,provider=Slug"`)
+	assert.Equal(t, "Slug", results.provider())
+
+	results, _ = parseTag(`fmgen:"optional"`)
+	assert.Empty(t, results.provider())
+}
+
+func TestTagGen(t *testing.T) {
+	results, _ := parseTag(`fmgen:"gen=newRequestID"`)
+	assert.Equal(t, "newRequestID", results.gen())
+	assert.Empty(t, results.provider())
+
+	results, _ = parseTag(`fmgen:"now"`)
+	assert.Equal(t, "time.Now", results.gen())
+	assert.Equal(t, "Now", results.provider())
+
+	results, _ = parseTag(`fmgen:"id"`)
+	assert.Empty(t, results.gen())
+}
+
+func TestTagNormalizers(t *testing.T) {
+	results, _ := parseTag(`fmgen:"optional,trim,lower,call=normalizeEmail"`)
+	assert.Equal(t, []string{"trim", "lower", "call=normalizeEmail"}, results.normalizers())
+
+	results, _ = parseTag(`fmgen:"sort,dedupe"`)
+	assert.Equal(t, []string{"sort", "dedupe"}, results.normalizers())
+
+	results, _ = parseTag(`fmgen:"utc,round=1ms"`)
+	assert.Equal(t, []string{"utc", "round=1ms"}, results.normalizers())
+
+	results, _ = parseTag(`fmgen:"optional"`)
+	assert.Empty(t, results.normalizers())
+}
+
+func TestTagInit(t *testing.T) {
+	results, _ := parseTag(`fmgen:"optional,init"`)
+	buffer, ok := results.init()
+	assert.True(t, ok)
+	assert.Empty(t, buffer)
+
+	results, _ = parseTag(`fmgen:"init=16"`)
+	buffer, ok = results.init()
+	assert.True(t, ok)
+	assert.Equal(t, "16", buffer)
+
+	results, _ = parseTag(`fmgen:"optional"`)
+	_, ok = results.init()
+	assert.False(t, ok)
+}
+
+func TestTagNil(t *testing.T) {
+	results, _ := parseTag(`fmgen:"notnil"`)
+	assert.True(t, results.notNil())
+	assert.False(t, results.allowNil())
+
+	results, _ = parseTag(`fmgen:"allownil"`)
+	assert.False(t, results.notNil())
+	assert.True(t, results.allowNil())
+}
+
+func TestTagConstraints(t *testing.T) {
+	results, _ := parseTag(`fmgen:"optional,oneof=source,anyof=contact,requires=Password,requires=Salt"`)
+	assert.Equal(t, "source", results.oneOf())
+	assert.Equal(t, "contact", results.anyOf())
+	assert.Equal(t, []string{"Password", "Salt"}, results.requires())
+
+	results, _ = parseTag(`fmgen:"optional"`)
+	assert.Empty(t, results.oneOf())
+	assert.Empty(t, results.anyOf())
+	assert.Empty(t, results.requires())
+}
+
+func TestTagCopy(t *testing.T) {
+	results, _ := parseTag(`fmgen:"optional,copy"`)
+	assert.True(t, results.copy())
+
+	results, _ = parseTag(`fmgen:"optional"`)
+	assert.False(t, results.copy())
+}
+
+func TestTagFlatten(t *testing.T) {
+	results, _ := parseTag(`fmgen:"flatten"`)
+	assert.Equal(t, "flatten", results.flatten())
+
+	results, _ = parseTag(`fmgen:"factory"`)
+	assert.Equal(t, "factory", results.flatten())
+
+	results, _ = parseTag(`fmgen:"optional"`)
+	assert.Empty(t, results.flatten())
+}
+
+func TestTagCtors(t *testing.T) {
+	results, _ := parseTag(`fmgen:"optional,ctor=Minimal|Full"`)
+	assert.Equal(t, []string{"Minimal", "Full"}, results.ctors())
+
+	results, _ = parseTag(`fmgen:"optional"`)
+	assert.Empty(t, results.ctors())
+}
+
+func TestTagOrder(t *testing.T) {
+	results, _ := parseTag(`fmgen:"optional,order=2"`)
+	assert.Equal(t, "2", results.order())
+
+	results, _ = parseTag(`fmgen:"optional"`)
+	assert.Empty(t, results.order())
+}
+
+func TestTagParam(t *testing.T) {
+	results, _ := parseTag(`fmgen:"optional,param=kind"`)
+	assert.Equal(t, "kind", results.param())
+
+	results, _ = parseTag(`fmgen:"optional"`)
+	assert.Empty(t, results.param())
+}
+
+func TestTagConv(t *testing.T) {
+	results, _ := parseTag(`fmgen:"conv=uuid.Parse"`)
+	conv, paramType := results.conv()
+	assert.Equal(t, "uuid.Parse", conv)
+	assert.Equal(t, "string", paramType)
+
+	results, _ = parseTag(`fmgen:"optional,conv=parseUnix,paramtype=int64"`)
+	conv, paramType = results.conv()
+	assert.Equal(t, "parseUnix", conv)
+	assert.Equal(t, "int64", paramType)
+
+	// a predeclared type passed to param declares the type of the param rather than its name
+	results, _ = parseTag(`fmgen:"param=string,conv=uuid.Parse"`)
+	conv, paramType = results.conv()
+	assert.Equal(t, "uuid.Parse", conv)
+	assert.Equal(t, "string", paramType)
+	assert.Empty(t, results.param())
+
+	results, _ = parseTag(`fmgen:"param=seconds,conv=parseSeconds,paramtype=int64"`)
+	conv, paramType = results.conv()
+	assert.Equal(t, "parseSeconds", conv)
+	assert.Equal(t, "int64", paramType)
+	assert.Equal(t, "seconds", results.param())
+
+	results, _ = parseTag(`fmgen:"optional"`)
+	conv, paramType = results.conv()
+	assert.Empty(t, conv)
+	assert.Empty(t, paramType)
 }
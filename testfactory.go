@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+func formatTestFactoryName(in string) string {
+	return capitalize(in) + "Factory"
+}
+
+func findField(s genStruct, name string) (genField, bool) {
+	for _, f := range s.fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return genField{}, false
+}
+
+// buildSeqField returns the statements assigning the sequence number n to the field
+func buildSeqField(s genStruct, f genField) string {
+	if f.array {
+		log.Panicf("seq tag is not supported on array field [%s] in struct [%s]", f.name, s.name)
+	}
+
+	var value string
+	switch f.typ {
+	case "string":
+		value = fmt.Sprintf("fmt.Sprintf(%q, n)", f.seq)
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte":
+		value = fmt.Sprintf("%s(n)", f.typ)
+	default:
+		log.Panicf("seq tag is not supported on field [%s] of type [%s] in struct [%s]", f.name, f.typ, s.name)
+	}
+
+	if f.ptr {
+		return fmt.Sprintf("{\nv := %s\nresult.%s = &v\n}\n", value, f.name)
+	}
+	return fmt.Sprintf("result.%s = %s\n", f.name, value)
+}
+
+// buildTraits returns the switch cases applying each trait declared on the struct
+func buildTraits(s genStruct) string {
+	var sb strings.Builder
+	seen := make(map[string]bool)
+
+	for _, t := range s.traits() {
+		if len(t.args) == 0 {
+			log.Panicf("trait in struct [%s] is missing a name", s.name)
+		}
+
+		traitName := t.args[0]
+		if seen[traitName] {
+			log.Panicf("trait [%s] is declared more than once in struct [%s]", traitName, s.name)
+		}
+		seen[traitName] = true
+
+		sb.WriteString(fmt.Sprintf("case %q:\n", traitName))
+		for _, arg := range t.args[1:] {
+			fieldName, value, ok := splitAssignment(arg)
+			if !ok {
+				log.Panicf("invalid trait [%s] value [%s] in struct [%s], expected Field=value", traitName, arg, s.name)
+			}
+			if _, ok := findField(s, fieldName); !ok {
+				log.Panicf("trait [%s] refers to unknown field [%s] in struct [%s]", traitName, fieldName, s.name)
+			}
+			sb.WriteString(fmt.Sprintf("result.%s = %s\n", fieldName, value))
+		}
+	}
+
+	return sb.String()
+}
+
+func writeTestFactory(w io.Writer, s genStruct) {
+	factoryName := formatTestFactoryName(s.name)
+	fixtureName := formatFixtureName(s)
+
+	fmt.Fprintf(w, "// %s generated test factory for %s with named traits and sequences\n", factoryName, s.name)
+	fmt.Fprintf(w, "type %s struct {\nseed int64\nseq *int64\ntraits []string\n}\n\n", factoryName)
+
+	ctorName := companionName(s, factoryName)
+	fmt.Fprintf(w, "// %s returns a new factory for %s, the same seed always builds the same values\n", ctorName, s.name)
+	fmt.Fprintf(w, "func %s(seed int64) *%s {\n", ctorName, factoryName)
+	fmt.Fprintf(w, "return &%s{seed: seed, seq: new(int64)}\n}\n\n", factoryName)
+
+	fmt.Fprintf(w, "// With returns a copy of the factory applying the named traits, sharing the same sequence\n")
+	fmt.Fprintf(w, "func (f *%s) With(traits ...string) *%s {\n", factoryName, factoryName)
+	fmt.Fprintf(w, "return &%s{seed: f.seed, seq: f.seq, traits: append(append([]string{}, f.traits...), traits...)}\n}\n\n", factoryName)
+
+	fmt.Fprintf(w, "// Build creates the next %s in the sequence, traits are applied before any overrides\n", s.name)
+	fmt.Fprintf(w, "func (f *%s) Build(overrides ...func(*%s)) *%s {\n", factoryName, s.name, s.name)
+	fmt.Fprintln(w, "*f.seq++")
+	fmt.Fprintln(w, "n := *f.seq")
+	fmt.Fprintf(w, "result := %s(f.seed + n)\n", fixtureName)
+	for _, f := range s.fields {
+		if f.seq != "" && !f.skip {
+			fmt.Fprint(w, buildSeqField(s, f))
+		}
+	}
+	fmt.Fprintln(w, "for _, t := range f.traits {")
+	fmt.Fprintln(w, "switch t {")
+	fmt.Fprint(w, buildTraits(s))
+	fmt.Fprintf(w, "default:\npanic(fmt.Sprintf(\"unknown %s trait %%q\", t))\n}\n}\n", factoryName)
+	fmt.Fprintln(w, "for _, o := range overrides {\no(result)\n}")
+	fmt.Fprintln(w, "return result")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "// BuildList creates the next n %s in the sequence\n", s.name)
+	fmt.Fprintf(w, "func (f *%s) BuildList(n int, overrides ...func(*%s)) []*%s {\n", factoryName, s.name, s.name)
+	fmt.Fprintf(w, "result := make([]*%s, 0, n)\n", s.name)
+	fmt.Fprintln(w, "for i := 0; i < n; i++ {\nresult = append(result, f.Build(overrides...))\n}")
+	fmt.Fprintln(w, "return result")
+	fmt.Fprintln(w, "}")
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func TestFormatTestFactoryName(t *testing.T) {
+	assert.Equal(t, "GenStructFactory", formatTestFactoryName("genStruct"))
+	assert.Equal(t, "TestFactory", formatTestFactoryName("Test"))
+}
+
+func TestBuildSeqField(t *testing.T) {
+	s := genStruct{name: "Sample"}
+
+	t.Run("string", func(t *testing.T) {
+		result := buildSeqField(s, genField{name: "Email", typ: "string", seq: "user-%d@example.com"})
+		assert.Equal(t, "result.Email = fmt.Sprintf(\"user-%d@example.com\", n)\n", result)
+	})
+
+	t.Run("int pointer", func(t *testing.T) {
+		result := buildSeqField(s, genField{name: "Count", typ: "int32", ptr: true, seq: "%d"})
+		assert.Equal(t, "{\nv := int32(n)\nresult.Count = &v\n}\n", result)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		assert.Panics(t, func() {
+			buildSeqField(s, genField{name: "Created", typ: "time.Time", seq: "%d"})
+		})
+		assert.Panics(t, func() {
+			buildSeqField(s, genField{name: "Tags", typ: "string", array: true, seq: "%d"})
+		})
+	})
+}
+
+func TestBuildTraits(t *testing.T) {
+	s := genStruct{
+		name: "Sample",
+		fields: []genField{
+			{name: "Role", typ: "string"},
+			{name: "Active", typ: "bool"},
+		},
+		comment: &genComment{value: "Sample\nfmgen:trait admin Role=\"admin\" Active=true\nfmgen:trait inactive Active=false\n"},
+	}
+
+	t.Run("traits", func(t *testing.T) {
+		expected := `case "admin":
+result.Role = "admin"
+result.Active = true
+case "inactive":
+result.Active = false
+`
+		assert.Equal(t, expected, buildTraits(s))
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		invalid := s
+		invalid.comment = &genComment{value: "fmgen:trait admin Missing=true"}
+		assert.Panics(t, func() {
+			buildTraits(invalid)
+		})
+	})
+
+	t.Run("duplicate trait", func(t *testing.T) {
+		invalid := s
+		invalid.comment = &genComment{value: "fmgen:trait admin\nfmgen:trait admin\n"}
+		assert.Panics(t, func() {
+			buildTraits(invalid)
+		})
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		invalid := s
+		invalid.comment = &genComment{value: "fmgen:trait admin Role"}
+		assert.Panics(t, func() {
+			buildTraits(invalid)
+		})
+	})
+}
+
+func TestWriteTestFactory(t *testing.T) {
+	var buf bytes.Buffer
+	s := genStruct{
+		name: "Sample",
+		fields: []genField{
+			{name: "Role", typ: "string"},
+			{name: "Email", typ: "string", seq: "user-%d"},
+		},
+		comment: &genComment{value: "fmgen:trait admin Role=\"admin\""},
+	}
+
+	writeGoFile(&buf, fixtureFileName, "testdata", nil, func(buf io.Writer) {
+		writeTestFactory(buf, s)
+	})
+
+	expected := `// Code generated by "fmgen". DO NOT EDIT.
+package testdata
+
+import "fmt"
+
+// SampleFactory generated test factory for Sample with named traits and sequences
+type SampleFactory struct {
+	seed   int64
+	seq    *int64
+	traits []string
+}
+
+// NewSampleFactory returns a new factory for Sample, the same seed always builds the same values
+func NewSampleFactory(seed int64) *SampleFactory {
+	return &SampleFactory{seed: seed, seq: new(int64)}
+}
+
+// With returns a copy of the factory applying the named traits, sharing the same sequence
+func (f *SampleFactory) With(traits ...string) *SampleFactory {
+	return &SampleFactory{seed: f.seed, seq: f.seq, traits: append(append([]string{}, f.traits...), traits...)}
+}
+
+// Build creates the next Sample in the sequence, traits are applied before any overrides
+func (f *SampleFactory) Build(overrides ...func(*Sample)) *Sample {
+	*f.seq++
+	n := *f.seq
+	result := NewSampleFixture(f.seed + n)
+	result.Email = fmt.Sprintf("user-%d", n)
+	for _, t := range f.traits {
+		switch t {
+		case "admin":
+			result.Role = "admin"
+		default:
+			panic(fmt.Sprintf("unknown SampleFactory trait %q", t))
+		}
+	}
+	for _, o := range overrides {
+		o(result)
+	}
+	return result
+}
+
+// BuildList creates the next n Sample in the sequence
+func (f *SampleFactory) BuildList(n int, overrides ...func(*Sample)) []*Sample {
+	result := make([]*Sample, 0, n)
+	for i := 0; i < n; i++ {
+		result = append(result, f.Build(overrides...))
+	}
+	return result
+}
+`
+	assert.Equal(t, expected, buf.String())
+
+	t.Run("declared name", func(t *testing.T) {
+		var buf bytes.Buffer
+		s.funcName = "CreateSample"
+		writeTestFactory(&buf, s)
+		assert.Contains(t, buf.String(), "func CreateSampleFactory(seed int64) *SampleFactory {\n")
+		assert.Contains(t, buf.String(), "result := CreateSampleFixture(f.seed + n)\n")
+	})
+}
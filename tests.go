@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+var testsImports = []string{`"fmt"`, `fmrand "math/rand"`, `"reflect"`, `"testing"`, `"time"`}
+
+func formatTestName(s genStruct) string {
+	return "Test" + s.factoryName()
+}
+
+// valueType returns the type of a value stored in the field, ignoring whether the field itself is a pointer
+func valueType(f genField) string {
+	switch {
+	case f.array && f.ptr:
+		return "[]*" + f.typ
+	case f.array:
+		return "[]" + f.typ
+	default:
+		return f.typ
+	}
+}
+
+// untestable returns the reason valid arguments can't be generated for the factory method of the struct, generated
+// tests and examples are skipped for these structs
+func untestable(s genStruct) (string, bool) {
+	if name, ok := uncreatableParam(s); ok {
+		return fmt.Sprintf("a non-nil [%s] can't be created", name), true
+	}
+	if reason, ok := constraintConflict(s.fields); ok {
+		return reason, true
+	}
+	if name, ok := unsettableParam(s); ok {
+		return fmt.Sprintf("a non-nil [%s] can't be created to satisfy its constraints", name), true
+	}
+	if hasConversions(s.fields) {
+		return "valid values for converted parameters can't be created", true
+	}
+	return "", false
+}
+
+// unsettableParam returns the name of the first constrained param set to satisfy the constraints which a non-nil value
+// can't be created for
+func unsettableParam(s genStruct) (string, bool) {
+	set := satisfyingFields(s.fields)
+	for _, p := range ownParams(s.fields) {
+		if !set[p.field.name] || !p.field.nilable() {
+			continue
+		}
+		if _, ok := nilCheckedValue(p.field); !ok {
+			return p.name, true
+		}
+	}
+	return "", false
+}
+
+// buildTestValue returns the statements declaring a variable with a random value for the parameter, true is returned
+// when the random source is used. The value is never nil when set is true.
+func buildTestValue(p genParam, set bool) (string, bool) {
+	f := p.field
+	decl := fmt.Sprintf("var %s %s\n", p.name, valueType(f))
+
+	value, ok := randomValue(f, "r")
+	if !ok {
+		// checked and set fields must not be nil
+		if v, ok := nilCheckedValue(f); ok && (nilChecked(f) || set) {
+			return fmt.Sprintf("%s := %s\n", p.name, v), false
+		}
+		return decl, false
+	}
+
+	if !f.array {
+		// non-array values are always stored directly in the variable
+		return fmt.Sprintf("%s := %s\n", p.name, value), true
+	}
+	return decl + assignRandom(f, p.name, value, false, "2"), true
+}
+
+// buildTestCheck returns the statements verifying the field of the result was assigned from the parameter
+func buildTestCheck(fmFuncName string, p genParam) string {
+	f := p.field
+	errorf := func(got, want string) string {
+		return fmt.Sprintf("t.Errorf(\"%s() %s = %%v, want %%v\", %s, %s)\n", fmFuncName, f.name, got, want)
+	}
+
+	param := p.name
+	if f.optional {
+		param = "tt." + p.name
+	}
+
+	switch {
+	case len(f.normalize) > 0:
+		// normalized fields are not expected to equal the parameter
+		return ""
+	case f.fn:
+		// funcs can only be compared to nil
+		return fmt.Sprintf("if (result.%s == nil) != (%s == nil) {\nt.Errorf(\"%s() %s is nil = %%t, want %%t\", result.%s == nil, %s == nil)\n}\n",
+			f.name, param, fmFuncName, f.name, f.name, param)
+	case f.init:
+		// a nil parameter is replaced with an empty value
+		return fmt.Sprintf("if %s == nil {\nif result.%s == nil || len(result.%s) != 0 {\nt.Errorf(\"%s() %s = %%v, want an empty value\", result.%s)\n}\n} else if !reflect.DeepEqual(result.%s, %s) {\n%s}\n",
+			param, f.name, f.name, fmFuncName, f.name, f.name, f.name, param, errorf("result."+f.name, param))
+	case f.optional:
+		got := "result." + f.name
+		want := "tt." + p.name
+		if f.ptr && !f.array {
+			got = "*" + got
+		}
+		if !f.nilable() {
+			want = "*" + want
+		}
+		return fmt.Sprintf("if tt.%s == nil {\nif !reflect.ValueOf(result.%s).IsZero() {\nt.Errorf(\"%s() %s = %%v, want the zero value\", result.%s)\n}\n} else if !reflect.DeepEqual(%s, %s) {\n%s}\n",
+			p.name, f.name, fmFuncName, f.name, f.name, got, want, errorf(got, want))
+	case f.ptr && !f.array:
+		return fmt.Sprintf("if result.%s == nil || !reflect.DeepEqual(*result.%s, %s) {\n%s}\n", f.name, f.name, p.name, errorf("result."+f.name, p.name))
+	default:
+		return fmt.Sprintf("if !reflect.DeepEqual(result.%s, %s) {\n%s}\n", f.name, p.name, errorf("result."+f.name, p.name))
+	}
+}
+
+func writeTest(w io.Writer, s genStruct) {
+	fmFuncName := s.factoryName()
+	if reason, ok := untestable(s); ok {
+		log.Printf("skipping tests for struct [%s], %s\n", s.name, reason)
+		return
+	}
+
+	params := factoryParams(s.policyFields())
+	constrained := satisfyingFields(s.fields)
+
+	var values, checks strings.Builder
+	var tableFields, setConstrained, setOptionals, callArgs []string
+	usesRand := false
+	for _, p := range params {
+		checks.WriteString(buildTestCheck(fmFuncName, p))
+		set, isConstrained := constrained[p.field.name]
+		if p.field.optional {
+			// optional parameters are passed in from each test case
+			tableFields = append(tableFields, fmt.Sprintf("%s %s", p.name, p.typ))
+			callArgs = append(callArgs, "tt."+p.name)
+		} else {
+			callArgs = append(callArgs, p.name)
+		}
+		if isConstrained && !set {
+			// constrained parameters which aren't set to satisfy the constraints are always nil
+			continue
+		}
+
+		value, random := buildTestValue(p, set)
+		values.WriteString(value)
+		usesRand = usesRand || random
+		if !p.field.optional {
+			continue
+		}
+
+		optional := fmt.Sprintf("%s: &%s", p.name, p.name)
+		if p.field.nilable() {
+			optional = fmt.Sprintf("%s: %s", p.name, p.name)
+		}
+		if isConstrained {
+			// constrained parameters are set in every test case to satisfy the constraints
+			setConstrained = append(setConstrained, optional)
+		} else {
+			setOptionals = append(setOptionals, optional)
+		}
+	}
+
+	for _, f := range s.fields {
+		if f.skip {
+			checks.WriteString(fmt.Sprintf("if !reflect.ValueOf(result.%s).IsZero() {\nt.Errorf(\"%s() %s = %%v, want the zero value\", result.%s)\n}\n", f.name, fmFuncName, f.name, f.name))
+		}
+	}
+
+	testName := formatTestName(s)
+	fmt.Fprintf(w, "// %s generated tests for %s\n", testName, fmFuncName)
+	fmt.Fprintf(w, "func %s(t *testing.T) {\n", testName)
+	if usesRand {
+		fmt.Fprintln(w, "r := fmrand.New(fmrand.NewSource(1))")
+	}
+	fmt.Fprint(w, values.String())
+
+	fmt.Fprintf(w, "tests := []struct {\ncaseName string\n%s}{\n", joinLines(tableFields))
+	if len(tableFields) > 0 {
+		fmt.Fprintf(w, "{%s},\n", strings.Join(append([]string{`caseName: "nil optional parameters"`}, setConstrained...), ", "))
+		fmt.Fprintf(w, "{%s},\n", strings.Join(append(append([]string{`caseName: "optional parameters"`}, setConstrained...), setOptionals...), ", "))
+	} else {
+		fmt.Fprintln(w, `{caseName: "required parameters"},`)
+	}
+	fmt.Fprintln(w, "}")
+
+	fmt.Fprintln(w, "for _, tt := range tests {")
+	fmt.Fprintln(w, "t.Run(tt.caseName, func(t *testing.T) {")
+	call := fmt.Sprintf("%s(%s)", fmFuncName, strings.Join(callArgs, ", "))
+	fmt.Fprint(w, buildCall(s.fields, "result", call, fmt.Sprintf("t.Fatalf(\"%s() error = %%v\", err)\n", fmFuncName)))
+	fmt.Fprintf(w, "if result == nil {\nt.Fatal(\"%s() returned nil\")\n}\n", fmFuncName)
+	fmt.Fprint(w, checks.String())
+	fmt.Fprintln(w, "})")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "}")
+}
+
+// joinLines joins each value on a separate line, including a trailing new line
+func joinLines(values []string) string {
+	var sb strings.Builder
+	for _, v := range values {
+		sb.WriteString(v + "\n")
+	}
+	return sb.String()
+}
+
+func writeTestsFile(w io.Writer, pkg string, pkgImports []string, structs []genStruct) {
+	log.Printf("generating factory method tests file for package [%s]", pkg)
+
+	writeGoFile(w, testsFileName, pkg, mergeImports(pkgImports, testsImports...), func(buf io.Writer) {
+		for _, s := range structs {
+			writeTest(buf, s)
+		}
+	})
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFormatTestName(t *testing.T) {
+	assert.Equal(t, "TestNewGenStruct", formatTestName(genStruct{name: "genStruct"}))
+	assert.Equal(t, "TestNewTest", formatTestName(genStruct{name: "Test"}))
+}
+
+func TestValueType(t *testing.T) {
+	assert.Equal(t, "string", valueType(genField{typ: "string"}))
+	assert.Equal(t, "string", valueType(genField{typ: "string", ptr: true}))
+	assert.Equal(t, "[]string", valueType(genField{typ: "string", array: true}))
+	assert.Equal(t, "[]*string", valueType(genField{typ: "string", array: true, ptr: true}))
+}
+
+func TestUntestable(t *testing.T) {
+	_, ok := untestable(genStruct{name: "Sample", fields: []genField{{name: "Name", typ: "string"}}})
+	assert.False(t, ok)
+
+	reason, ok := untestable(genStruct{name: "Sample", fields: []genField{{name: "Logger", typ: "Logger", iface: true}}})
+	assert.True(t, ok)
+	assert.Equal(t, "a non-nil [Logger] can't be created", reason)
+
+	_, ok = untestable(genStruct{name: "Sample", fields: []genField{
+		{name: "File", typ: "string", optional: true, oneOf: "source"},
+		{name: "URL", typ: "string", optional: true, oneOf: "source"},
+	}})
+	assert.False(t, ok)
+
+	reason, ok = untestable(genStruct{name: "Sample", fields: []genField{
+		{name: "Handler", typ: "func()", fn: true, optional: true, anyOf: "handler"},
+		{name: "Name", typ: "string", optional: true, anyOf: "handler"},
+	}})
+	assert.True(t, ok)
+	assert.Equal(t, "a non-nil [Handler] can't be created to satisfy its constraints", reason)
+
+	reason, ok = untestable(genStruct{name: "Sample", fields: []genField{
+		{name: "File", typ: "string", optional: true, oneOf: "source", anyOf: "input"},
+		{name: "URL", typ: "string", optional: true, oneOf: "source", anyOf: "input"},
+	}})
+	assert.True(t, ok)
+	assert.Equal(t, "[File] is in both oneof group [source] and anyof group [input]", reason)
+}
+
+func TestBuildTestValue(t *testing.T) {
+	t.Run("pointer", func(t *testing.T) {
+		f := genField{name: "Active", typ: "bool", ptr: true}
+		result, random := buildTestValue(genParam{name: "Active", typ: "bool", field: f}, false)
+		assert.True(t, random)
+		assert.Equal(t, "Active := r.Intn(2) == 1\n", result)
+	})
+
+	t.Run("array", func(t *testing.T) {
+		f := genField{name: "Active", typ: "bool", array: true}
+		result, random := buildTestValue(genParam{name: "Active", typ: "[]bool", field: f}, false)
+		assert.True(t, random)
+		expected := `var Active []bool
+for i := 0; i < 2; i++ {
+Active = append(Active, r.Intn(2) == 1)
+}
+`
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		f := genField{name: "BaseURL", typ: "url.URL", optional: true}
+		result, random := buildTestValue(genParam{name: "BaseURL", typ: "*url.URL", field: f}, false)
+		assert.False(t, random)
+		assert.Equal(t, "var BaseURL url.URL\n", result)
+	})
+}
+
+func TestBuildTestCheck(t *testing.T) {
+	t.Run("required pointer", func(t *testing.T) {
+		f := genField{name: "Active", typ: "bool", ptr: true}
+		expected := `if result.Active == nil || !reflect.DeepEqual(*result.Active, Active) {
+t.Errorf("NewSample() Active = %v, want %v", result.Active, Active)
+}
+`
+		assert.Equal(t, expected, buildTestCheck("NewSample", genParam{name: "Active", typ: "bool", field: f}))
+	})
+
+	t.Run("optional array", func(t *testing.T) {
+		f := genField{name: "Tags", typ: "string", array: true, optional: true}
+		expected := `if tt.Tags == nil {
+if !reflect.ValueOf(result.Tags).IsZero() {
+t.Errorf("NewSample() Tags = %v, want the zero value", result.Tags)
+}
+} else if !reflect.DeepEqual(result.Tags, tt.Tags) {
+t.Errorf("NewSample() Tags = %v, want %v", result.Tags, tt.Tags)
+}
+`
+		assert.Equal(t, expected, buildTestCheck("NewSample", genParam{name: "Tags", typ: "[]string", field: f}))
+	})
+
+	t.Run("initialized", func(t *testing.T) {
+		f := genField{name: "Attrs", typ: "map[string]int", mapped: true, init: true}
+		expected := `if Attrs == nil {
+if result.Attrs == nil || len(result.Attrs) != 0 {
+t.Errorf("NewSample() Attrs = %v, want an empty value", result.Attrs)
+}
+} else if !reflect.DeepEqual(result.Attrs, Attrs) {
+t.Errorf("NewSample() Attrs = %v, want %v", result.Attrs, Attrs)
+}
+`
+		assert.Equal(t, expected, buildTestCheck("NewSample", genParam{name: "Attrs", typ: "map[string]int", field: f}))
+	})
+
+	t.Run("func", func(t *testing.T) {
+		f := genField{name: "Notify", typ: "func()", fn: true, optional: true}
+		expected := `if (result.Notify == nil) != (tt.Notify == nil) {
+t.Errorf("NewSample() Notify is nil = %t, want %t", result.Notify == nil, tt.Notify == nil)
+}
+`
+		assert.Equal(t, expected, buildTestCheck("NewSample", genParam{name: "Notify", typ: "func()", field: f}))
+	})
+
+	t.Run("normalized", func(t *testing.T) {
+		f := genField{name: "Email", typ: "string", normalize: []string{"trim"}}
+		assert.Empty(t, buildTestCheck("NewSample", genParam{name: "Email", typ: "string", field: f}))
+	})
+}
+
+func TestWriteTestsFile(t *testing.T) {
+	buf := bytes.Buffer{}
+	structs := []genStruct{
+		{
+			name: "Sample",
+			fields: []genField{
+				{name: "ID", typ: "int64", skip: true},
+				{name: "Name", typ: "string"},
+				{name: "Age", typ: "int64", optional: true},
+			},
+		},
+		{
+			name: "Config",
+			fields: []genField{
+				{name: "File", typ: "string", optional: true, oneOf: "source"},
+				{name: "Inline", typ: "byte", array: true, optional: true, oneOf: "source"},
+				{name: "Username", typ: "string", optional: true, requires: []string{"Password"}},
+				{name: "Password", typ: "string", optional: true},
+				{name: "Attrs", typ: "map[string]int", mapped: true, optional: true},
+			},
+		},
+		{
+			name: "Unsupported",
+			fields: []genField{
+				{name: "BaseURL", typ: "url.URL"},
+			},
+		},
+	}
+
+	expected := `// Code generated by "fmgen". DO NOT EDIT.
+package testdata
+
+import (
+	"fmt"
+	fmrand "math/rand"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// TestNewSample generated tests for NewSample
+func TestNewSample(t *testing.T) {
+	r := fmrand.New(fmrand.NewSource(1))
+	Name := []string{"Alice", "Bob", "Carol", "Dave", "Erin", "Frank"}[r.Intn(6)]
+	Age := int64(18 + r.Intn(60))
+	tests := []struct {
+		caseName string
+		Age      *int64
+	}{
+		{caseName: "nil optional parameters"},
+		{caseName: "optional parameters", Age: &Age},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caseName, func(t *testing.T) {
+			result := NewSample(Name, tt.Age)
+			if result == nil {
+				t.Fatal("NewSample() returned nil")
+			}
+			if !reflect.DeepEqual(result.Name, Name) {
+				t.Errorf("NewSample() Name = %v, want %v", result.Name, Name)
+			}
+			if tt.Age == nil {
+				if !reflect.ValueOf(result.Age).IsZero() {
+					t.Errorf("NewSample() Age = %v, want the zero value", result.Age)
+				}
+			} else if !reflect.DeepEqual(result.Age, *tt.Age) {
+				t.Errorf("NewSample() Age = %v, want %v", result.Age, *tt.Age)
+			}
+			if !reflect.ValueOf(result.ID).IsZero() {
+				t.Errorf("NewSample() ID = %v, want the zero value", result.ID)
+			}
+		})
+	}
+}
+
+// TestNewConfig generated tests for NewConfig
+func TestNewConfig(t *testing.T) {
+	r := fmrand.New(fmrand.NewSource(1))
+	File := fmt.Sprintf("file-%d", r.Intn(10000))
+	Username := []string{"Alice", "Bob", "Carol", "Dave", "Erin", "Frank"}[r.Intn(6)]
+	Password := fmt.Sprintf("password-%d", r.Intn(10000))
+	var Attrs map[string]int
+	tests := []struct {
+		caseName string
+		File     *string
+		Inline   []byte
+		Username *string
+		Password *string
+		Attrs    map[string]int
+	}{
+		{caseName: "nil optional parameters", File: &File, Username: &Username, Password: &Password},
+		{caseName: "optional parameters", File: &File, Username: &Username, Password: &Password, Attrs: Attrs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caseName, func(t *testing.T) {
+			result, err := NewConfig(tt.File, tt.Inline, tt.Username, tt.Password, tt.Attrs)
+			if err != nil {
+				t.Fatalf("NewConfig() error = %v", err)
+			}
+			if result == nil {
+				t.Fatal("NewConfig() returned nil")
+			}
+			if tt.File == nil {
+				if !reflect.ValueOf(result.File).IsZero() {
+					t.Errorf("NewConfig() File = %v, want the zero value", result.File)
+				}
+			} else if !reflect.DeepEqual(result.File, *tt.File) {
+				t.Errorf("NewConfig() File = %v, want %v", result.File, *tt.File)
+			}
+			if tt.Inline == nil {
+				if !reflect.ValueOf(result.Inline).IsZero() {
+					t.Errorf("NewConfig() Inline = %v, want the zero value", result.Inline)
+				}
+			} else if !reflect.DeepEqual(result.Inline, tt.Inline) {
+				t.Errorf("NewConfig() Inline = %v, want %v", result.Inline, tt.Inline)
+			}
+			if tt.Username == nil {
+				if !reflect.ValueOf(result.Username).IsZero() {
+					t.Errorf("NewConfig() Username = %v, want the zero value", result.Username)
+				}
+			} else if !reflect.DeepEqual(result.Username, *tt.Username) {
+				t.Errorf("NewConfig() Username = %v, want %v", result.Username, *tt.Username)
+			}
+			if tt.Password == nil {
+				if !reflect.ValueOf(result.Password).IsZero() {
+					t.Errorf("NewConfig() Password = %v, want the zero value", result.Password)
+				}
+			} else if !reflect.DeepEqual(result.Password, *tt.Password) {
+				t.Errorf("NewConfig() Password = %v, want %v", result.Password, *tt.Password)
+			}
+			if tt.Attrs == nil {
+				if !reflect.ValueOf(result.Attrs).IsZero() {
+					t.Errorf("NewConfig() Attrs = %v, want the zero value", result.Attrs)
+				}
+			} else if !reflect.DeepEqual(result.Attrs, tt.Attrs) {
+				t.Errorf("NewConfig() Attrs = %v, want %v", result.Attrs, tt.Attrs)
+			}
+		})
+	}
+}
+
+// TestNewUnsupported generated tests for NewUnsupported
+func TestNewUnsupported(t *testing.T) {
+	var BaseURL url.URL
+	tests := []struct {
+		caseName string
+	}{
+		{caseName: "required parameters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caseName, func(t *testing.T) {
+			result := NewUnsupported(BaseURL)
+			if result == nil {
+				t.Fatal("NewUnsupported() returned nil")
+			}
+			if !reflect.DeepEqual(result.BaseURL, BaseURL) {
+				t.Errorf("NewUnsupported() BaseURL = %v, want %v", result.BaseURL, BaseURL)
+			}
+		})
+	}
+}
+`
+
+	writeTestsFile(&buf, "testdata", []string{`"net/url"`}, structs)
+	assert.Equal(t, expected, buf.String())
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"go/token"
+	"log"
 	"strings"
 )
 
@@ -16,6 +17,52 @@ type genField struct {
 	skip     bool
 	ptr      bool
 	array    bool
+	// map, channel, func and interface fields keep their full type in typ
+	mapped   bool
+	channel  bool
+	fn       bool
+	iface    bool
+	seq      string
+	provider string
+	// function called to compute the field instead of passing it in
+	gen string
+	// tags normalizing the input before it is assigned
+	normalize []string
+	// copy the input so the caller's array or map is not shared
+	copy bool
+	// initialize the field when nil is passed in, with the buffer size of a channel
+	init   bool
+	buffer string
+	// reject a nil input even when fmgen can't tell it may be nil, or accept a nil input which would be rejected
+	notNil   bool
+	allowNil bool
+	// constraints between optional fields
+	oneOf    string
+	anyOf    string
+	requires []string
+	// fmgen:"flatten" or fmgen:"factory" on a struct field, along with the fields of the struct
+	flatten string
+	nested  []genField
+	// factory method creating the struct of a flattened field
+	nestedFunc string
+	// named constructors the field is required in, it is optional in the other named constructors
+	ctors []string
+	// position of the param declared with fmgen:"order=N", along with the order policy of the struct
+	order   string
+	orderBy string
+	// name of the input param, declared with fmgen:"param=name" or assigned from the field name
+	param string
+	// doc or line comment of the field
+	doc string
+	// function converting the param to the type of the field, along with the type of the param
+	conv      string
+	paramType string
+}
+
+// nilable returns true for arrays, maps, channels and funcs, these are passed in with their own type as nil already
+// means missing
+func (f genField) nilable() bool {
+	return f.array || f.mapped || f.channel || f.fn
 }
 
 type genComment struct {
@@ -24,18 +71,25 @@ type genComment struct {
 }
 
 type genStruct struct {
-	name    string
+	name string
+	// factory method name, declared with fmgen:name or from the -name template
+	funcName string
+	// base name of the file declaring the struct
+	file    string
 	lineNum int
 	fields  []genField
 	comment *genComment
+	// methods declared on the struct, mapped to whether the receiver is a pointer
+	methods map[string]bool
 }
 
-func (g genStruct) Skip() bool {
+// skipType returns true when the comment contains a skip directive, or the type was not included with the -s flag
+func skipType(name string, comment *genComment) bool {
 
 	// check comment for skip directive
-	if g.comment != nil {
+	if comment != nil {
 		for _, c := range skipStructComment {
-			if strings.Contains(strings.ToLower(g.comment.value), c) {
+			if strings.Contains(strings.ToLower(comment.value), c) {
 				return true
 			}
 		}
@@ -49,7 +103,7 @@ func (g genStruct) Skip() bool {
 	skip := true
 	structSplit := strings.Split(*flagStructs, ",")
 	for _, s := range structSplit {
-		if strings.TrimSpace(s) == g.name {
+		if strings.TrimSpace(s) == name {
 			skip = false
 		}
 	}
@@ -57,19 +111,159 @@ func (g genStruct) Skip() bool {
 	return skip
 }
 
+func (g genStruct) Skip() bool {
+	return skipType(g.name, g.comment)
+}
+
+// traits returns the named traits declared in the struct comment with fmgen:trait
+func (g genStruct) traits() []directive {
+	return findDirectives(g.comment, "trait")
+}
+
+// pooled returns true when the struct comment contains fmgen:pool
+func (g genStruct) pooled() bool {
+	return len(findDirectives(g.comment, "pool")) > 0
+}
+
+// copied returns true when the struct comment contains fmgen:copy or the -copy flag is set, copying every array and
+// map passed in
+func (g genStruct) copied() bool {
+	return *flagCopy || len(findDirectives(g.comment, "copy")) > 0
+}
+
+// initialized returns true when the struct comment contains fmgen:init, initializing every optional array, map and
+// channel when nil is passed in
+func (g genStruct) initialized() bool {
+	return len(findDirectives(g.comment, "init")) > 0
+}
+
+// policyFields returns the fields with the policies of the struct applied
+func (g genStruct) policyFields() []genField {
+	fields := make([]genField, len(g.fields))
+	for i, f := range g.fields {
+		if g.copied() && (f.array || f.mapped) {
+			f.copy = true
+		}
+		if g.initialized() && f.optional && (f.array || f.mapped || f.channel) {
+			f.init = true
+		}
+		fields[i] = f
+	}
+	return fields
+}
+
+// injected returns true when the struct comment contains fmgen:inject, making it the root of an injector
+func (g genStruct) injected() bool {
+	return len(findDirectives(g.comment, "inject")) > 0
+}
+
+// genArg is a single parameter or result of an interface method
+type genArg struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+type genMethod struct {
+	name    string
+	params  []genArg
+	results []genArg
+}
+
+type genInterface struct {
+	name    string
+	lineNum int
+	methods []genMethod
+	embeds  []string
+	comment *genComment
+}
+
+func (g genInterface) Skip() bool {
+	return skipType(g.name, g.comment)
+}
+
+// sealed returns true when the interface comment contains fmgen:sealed
+func (g genInterface) sealed() bool {
+	return len(findDirectives(g.comment, "sealed")) > 0
+}
+
+// genReceiver is a method declared on a named type
+type genReceiver struct {
+	typ    string
+	method string
+	ptr    bool
+}
+
+// genType is a defined type with a basic underlying type, such as type Status string
+type genType struct {
+	name       string
+	lineNum    int
+	underlying string
+	// names of the constants declared with the type
+	consts  []string
+	comment *genComment
+	// generated names which are already declared in the package
+	declared []string
+}
+
+func (g genType) Skip() bool {
+	return skipType(g.name, g.comment)
+}
+
+// enum returns true when the type comment contains fmgen:enum, limiting its values to the declared constants
+func (g genType) enum() bool {
+	return len(findDirectives(g.comment, "enum")) > 0
+}
+
+// generated returns true when the type is declared as an enum or declares a range to validate against
+func (g genType) generated() bool {
+	min, max := g.valueRange()
+	return g.enum() || min != "" || max != ""
+}
+
+// valueRange returns the min and max values declared in the type comment with fmgen:range min=1 max=10, an empty
+// string is returned for any bound which isn't declared
+func (g genType) valueRange() (string, string) {
+	var min, max string
+	for _, d := range findDirectives(g.comment, "range") {
+		for _, arg := range d.args {
+			name, value, ok := splitAssignment(arg)
+			switch {
+			case ok && name == "min":
+				min = value
+			case ok && name == "max":
+				max = value
+			default:
+				log.Panicf("invalid range [%s] for type [%s], expected min=value or max=value", arg, g.name)
+			}
+		}
+	}
+	return min, max
+}
+
+// genConst is a constant declared with a named type
+type genConst struct {
+	name string
+	typ  string
+}
+
 type genPackage struct {
-	dirname string
-	pkg     string
-	fset    *token.FileSet
-	structs []genStruct
-	imports []string
+	dirname    string
+	pkg        string
+	fset       *token.FileSet
+	structs    []genStruct
+	interfaces []genInterface
+	types      []genType
+	imports    []string
 }
 
 type genFile struct {
-	dirname  string
-	filename string
-	pkg      string
-	fset     *token.FileSet
-	structs  []genStruct
-	imports  []string
+	dirname    string
+	filename   string
+	pkg        string
+	fset       *token.FileSet
+	structs    []genStruct
+	interfaces []genInterface
+	types      []genType
+	imports    []string
 }
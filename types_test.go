@@ -63,6 +63,72 @@ func TestGenStruct(t *testing.T) {
 		assert.False(t, s.Skip())
 	})
 
+	t.Run("pooled", func(t *testing.T) {
+		s := genStruct{
+			comment: &genComment{value: "Sample struct\nfmgen:pool\n"},
+		}
+
+		assert.True(t, s.pooled())
+		assert.False(t, genStruct{}.pooled())
+	})
+
+	t.Run("copied", func(t *testing.T) {
+		s := genStruct{
+			comment: &genComment{value: "Sample struct fmgen:copy\n"},
+			fields: []genField{
+				{name: "Name", typ: "string"},
+				{name: "Tags", typ: "string", array: true},
+				{name: "Attrs", typ: "map[string]int", mapped: true},
+			},
+		}
+
+		assert.True(t, s.copied())
+		assert.False(t, genStruct{}.copied())
+		fields := s.policyFields()
+		assert.False(t, fields[0].copy)
+		assert.True(t, fields[1].copy)
+		assert.True(t, fields[2].copy)
+
+		// the struct fields should not be modified
+		assert.False(t, s.fields[1].copy)
+
+		*flagCopy = true
+		defer func() { *flagCopy = false }()
+		assert.True(t, genStruct{}.copied())
+	})
+
+	t.Run("initialized", func(t *testing.T) {
+		s := genStruct{
+			comment: &genComment{value: "Sample struct fmgen:init\n"},
+			fields: []genField{
+				{name: "Name", typ: "string", optional: true},
+				{name: "Tags", typ: "string", array: true},
+				{name: "Attrs", typ: "map[string]int", mapped: true, optional: true},
+				{name: "Events", typ: "chan int", channel: true, optional: true},
+			},
+		}
+
+		assert.True(t, s.initialized())
+		assert.False(t, genStruct{}.initialized())
+
+		// only optional arrays, maps and channels are initialized
+		fields := s.policyFields()
+		assert.False(t, fields[0].init)
+		assert.False(t, fields[1].init)
+		assert.True(t, fields[2].init)
+		assert.True(t, fields[3].init)
+		assert.False(t, fields[3].copy)
+	})
+
+	t.Run("injected", func(t *testing.T) {
+		s := genStruct{
+			comment: &genComment{value: "App is the application\nfmgen:inject\n"},
+		}
+
+		assert.True(t, s.injected())
+		assert.False(t, genStruct{}.injected())
+	})
+
 	t.Run("not in struct includes", func(t *testing.T) {
 		*flagStructs = "struct2,struct3"
 		s := genStruct{
@@ -81,3 +147,71 @@ func TestGenStruct(t *testing.T) {
 		assert.False(t, s.Skip())
 	})
 }
+
+func TestGenInterface(t *testing.T) {
+	*flagStructs = ""
+
+	t.Run("skip interface based on comment fmgen:-", func(t *testing.T) {
+		i := genInterface{
+			name:    "iface",
+			comment: &genComment{value: "Simple interface fmgen:-"},
+		}
+
+		assert.True(t, i.Skip())
+		assert.False(t, i.sealed())
+	})
+
+	t.Run("sealed", func(t *testing.T) {
+		i := genInterface{
+			name:    "Event",
+			comment: &genComment{value: "Event is a domain event\nfmgen:sealed\n"},
+		}
+
+		assert.False(t, i.Skip())
+		assert.True(t, i.sealed())
+	})
+
+	t.Run("no comment", func(t *testing.T) {
+		i := genInterface{name: "iface"}
+
+		assert.False(t, i.Skip())
+		assert.False(t, i.sealed())
+	})
+}
+
+func TestGenType(t *testing.T) {
+	*flagStructs = ""
+
+	t.Run("range", func(t *testing.T) {
+		typ := genType{name: "Port", comment: &genComment{value: "Port is a network port\nfmgen:range min=1 max=65535\n"}}
+		min, max := typ.valueRange()
+		assert.Equal(t, "1", min)
+		assert.Equal(t, "65535", max)
+		assert.True(t, typ.generated())
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		typ := genType{name: "Port", comment: &genComment{value: "fmgen:range 1"}}
+		assert.Panics(t, func() {
+			typ.valueRange()
+		})
+	})
+
+	t.Run("enum", func(t *testing.T) {
+		typ := genType{name: "Status", consts: []string{"StatusActive"}, comment: &genComment{value: "fmgen:enum"}}
+		assert.True(t, typ.enum())
+		assert.True(t, typ.generated())
+		assert.False(t, typ.Skip())
+	})
+
+	t.Run("constants without enum", func(t *testing.T) {
+		typ := genType{name: "Port", consts: []string{"DefaultPort"}}
+		assert.False(t, typ.enum())
+		assert.False(t, typ.generated())
+	})
+
+	t.Run("no constants or range", func(t *testing.T) {
+		typ := genType{name: "ID"}
+		assert.False(t, typ.generated())
+	})
+}